@@ -19,9 +19,9 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// Set up the window size and title
-	ebiten.SetWindowSize(game.ScreenWidth, game.ScreenHeight)
+	// Set up the window title, the window is sized from the profile's settings
 	ebiten.SetWindowTitle("The Go Runner")
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 
 	// Run the game loop
 	if err := ebiten.RunGame(g); err != nil {
@@ -0,0 +1,202 @@
+package achievement
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/tejashwikalptaru/go.run/game/event"
+	"github.com/tejashwikalptaru/go.run/game/locale"
+	"github.com/tejashwikalptaru/go.run/game/profile"
+	"github.com/tejashwikalptaru/go.run/game/ui"
+)
+
+// Event is something that happened in the game that achievements can count
+type Event string
+
+const (
+	EventObstacleCleared Event = "obstacle_cleared"
+	EventCloseCall       Event = "close_call"
+	EventCollision       Event = "collision"
+	EventCoinCollected   Event = "coin_collected"
+	EventPowerUpUsed     Event = "powerup_used"
+	EventScore           Event = "score"
+	EventCombo           Event = "combo"
+	EventLevelStarted    Event = "level_started"
+	EventLevelCleared    Event = "level_cleared"
+	EventBossDefeated    Event = "boss_defeated"
+)
+
+// Definition describes an achievement, definitions are loaded from data so new ones don't need code changes, the name and
+// description are the messages achievement.<id> and achievement.<id>.description
+type Definition struct {
+	ID       string `json:"id"`
+	Event    Event  `json:"event"`
+	Subject  string `json:"subject,omitempty"` // Only events about this subject count, like an obstacle type
+	FailOn   Event  `json:"failOn,omitempty"`  // This event rules the achievement out until the next run
+	Goal     int    `json:"goal"`
+	UseValue bool   `json:"useValue,omitempty"` // Progress is the highest event value instead of the sum of all values
+	PerRun   bool   `json:"perRun,omitempty"`   // Progress starts over with every run
+}
+
+// LoadDefinitions parses and validates the achievement definitions
+func LoadDefinitions(data []byte) ([]Definition, error) {
+	var definitions []Definition
+	if unmarshalErr := json.Unmarshal(data, &definitions); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	seen := make(map[string]bool, len(definitions))
+	for i, d := range definitions {
+		switch {
+		case d.ID == "":
+			return nil, fmt.Errorf("achievement %d has no id", i+1)
+		case seen[d.ID]:
+			return nil, fmt.Errorf("achievement %q is defined twice", d.ID)
+		case d.Event == "":
+			return nil, fmt.Errorf("achievement %q has no event", d.ID)
+		case d.Goal <= 0:
+			return nil, fmt.Errorf("achievement %q needs a goal above zero", d.ID)
+		}
+		seen[d.ID] = true
+	}
+	return definitions, nil
+}
+
+// Achievements tracks the progress of every achievement and unlocks them
+type Achievements struct {
+	profile     *profile.Profile
+	messages    *locale.Messages
+	toasts      *toasts
+	runProgress map[string]int
+	failed      map[string]bool
+	definitions []Definition
+	practice    bool // Nothing counts, like in the tutorial
+}
+
+func NewAchievements(definitions []Definition, playerProfile *profile.Profile, uiText *ui.Text, messages *locale.Messages, screenWidth float64) *Achievements {
+	return &Achievements{
+		profile:     playerProfile,
+		messages:    messages,
+		toasts:      newToasts(uiText, screenWidth),
+		runProgress: map[string]int{},
+		failed:      map[string]bool{},
+		definitions: definitions,
+	}
+}
+
+// Listen counts the game events towards the achievements
+func (a *Achievements) Listen(events *event.Bus) {
+	event.Subscribe(events, func(e event.ObstacleCleared) {
+		a.Notify(EventObstacleCleared, e.Type, 1)
+		if e.CloseCall {
+			a.Notify(EventCloseCall, e.Type, 1)
+		}
+	})
+	event.Subscribe(events, func(e event.ScoreChanged) {
+		a.Notify(EventScore, "", e.Score)
+		a.Notify(EventCombo, "", e.Combo)
+	})
+	event.Subscribe(events, func(e event.Collision) {
+		if !e.PowerUp {
+			a.Notify(EventCollision, e.Type, 1)
+		}
+	})
+	event.Subscribe(events, func(e event.PowerUpUsed) {
+		a.Notify(EventPowerUpUsed, e.Name, 1)
+	})
+	event.Subscribe(events, func(e event.CoinsCollected) {
+		a.Notify(EventCoinCollected, "", e.Count)
+	})
+	event.Subscribe(events, func(e event.LevelStarted) {
+		a.Notify(EventLevelStarted, "", e.Level)
+	})
+	event.Subscribe(events, func(e event.LevelCleared) {
+		a.Notify(EventLevelCleared, "", e.Level)
+		if e.Boss {
+			a.Notify(EventBossDefeated, "", 1)
+		}
+	})
+}
+
+// SetProfile tracks the achievements of another profile, the progress of the current run is dropped
+func (a *Achievements) SetProfile(playerProfile *profile.Profile) {
+	a.profile = playerProfile
+	a.StartRun()
+}
+
+// StartRun starts over the progress of the achievements that have to be reached in a single run
+func (a *Achievements) StartRun() {
+	a.runProgress = map[string]int{}
+	a.failed = map[string]bool{}
+}
+
+// SetPractice stops counting events while the player practices, like in the tutorial
+func (a *Achievements) SetPractice(practice bool) {
+	a.practice = practice
+}
+
+// Notify counts an event towards the achievements listening to it, value is the amount or the reached value
+func (a *Achievements) Notify(e Event, subject string, value int) {
+	if a.practice {
+		return
+	}
+	for _, d := range a.definitions {
+		if a.profile.IsUnlocked(d.ID) {
+			continue
+		}
+		if d.FailOn == e {
+			a.failed[d.ID] = true
+			continue
+		}
+		if d.Event != e || a.failed[d.ID] || (d.Subject != "" && d.Subject != subject) {
+			continue
+		}
+
+		progress := a.progress(d)
+		if d.UseValue {
+			progress = max(progress, value)
+		} else {
+			progress += value
+		}
+		a.setProgress(d, progress)
+
+		if progress >= d.Goal {
+			a.unlock(d)
+		}
+	}
+}
+
+// progress returns how far the achievement got
+func (a *Achievements) progress(d Definition) int {
+	if d.PerRun {
+		return a.runProgress[d.ID]
+	}
+	return a.profile.AchievementProgress(d.ID)
+}
+
+// setProgress stores the progress, lifetime progress is kept in the profile
+func (a *Achievements) setProgress(d Definition, progress int) {
+	if d.PerRun {
+		a.runProgress[d.ID] = progress
+		return
+	}
+	a.profile.SetAchievementProgress(d.ID, progress)
+}
+
+// unlock marks the achievement as reached, saves it and shows a toast
+func (a *Achievements) unlock(d Definition) {
+	a.profile.Unlock(d.ID)
+	if saveErr := a.profile.Save(); saveErr != nil {
+		fmt.Printf("failed to save profile: %v", saveErr)
+	}
+	// The description counts the goal, like Clear 50 snakes
+	a.toasts.add(a.messages.Text("achievement."+d.ID), a.messages.Plural("achievement."+d.ID+".description", d.Goal))
+}
+
+func (a *Achievements) Update() {
+	a.toasts.update()
+}
+
+func (a *Achievements) Draw(screen *ebiten.Image) {
+	a.toasts.draw(screen)
+}
@@ -0,0 +1,83 @@
+package achievement
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tejashwikalptaru/go.run/game/ui"
+)
+
+const (
+	toastFrames      = 180 // How long a toast stays on screen
+	toastSlideFrames = 15  // How long a toast takes to slide in and out
+	toastWidth       = 300
+	toastHeight      = 52
+	toastMargin      = 10
+	toastTitleSize   = 18
+	toastTextSize    = 12
+)
+
+type toast struct {
+	title       string
+	description string
+	ticks       int
+}
+
+// toasts shows unlocked achievements one after the other in the top right corner
+type toasts struct {
+	text        *ui.Text
+	queue       []toast
+	screenWidth float64
+}
+
+func newToasts(uiText *ui.Text, screenWidth float64) *toasts {
+	return &toasts{
+		text:        uiText,
+		screenWidth: screenWidth,
+	}
+}
+
+func (t *toasts) add(title, description string) {
+	t.queue = append(t.queue, toast{title: title, description: description})
+}
+
+func (t *toasts) update() {
+	if len(t.queue) == 0 {
+		return
+	}
+	t.queue[0].ticks++
+	if t.queue[0].ticks >= toastFrames {
+		t.queue = t.queue[1:] // Show the next toast
+	}
+}
+
+func (t *toasts) draw(screen *ebiten.Image) {
+	if len(t.queue) == 0 {
+		return
+	}
+	current := t.queue[0]
+
+	// Slide in from the right at the start and back out at the end
+	slide := 1.0
+	switch {
+	case current.ticks < toastSlideFrames:
+		slide = float64(current.ticks) / toastSlideFrames
+	case current.ticks > toastFrames-toastSlideFrames:
+		slide = float64(toastFrames-current.ticks) / toastSlideFrames
+	}
+
+	titleStyle := ui.Style{Size: toastTitleSize, Color: color.RGBA{R: 255, G: 210, B: 40, A: 255}}
+	descriptionStyle := ui.Style{Size: toastTextSize, Color: color.RGBA{R: 220, G: 220, B: 220, A: 255}, VAlign: ui.AlignEnd}
+
+	// Long titles and descriptions, like in some translations, widen the toast
+	width := max(toastWidth, t.text.Width(current.title, titleStyle)+20, t.text.Width(current.description, descriptionStyle)+20)
+	box := ui.Rect{X: t.screenWidth - (width+toastMargin)*slide, Y: toastMargin, Width: width, Height: toastHeight}
+
+	vector.DrawFilledRect(screen, float32(box.X), float32(box.Y), float32(box.Width), float32(box.Height), color.RGBA{R: 20, G: 20, B: 20, A: 220}, false)
+	vector.StrokeRect(screen, float32(box.X), float32(box.Y), float32(box.Width), float32(box.Height), 2, color.RGBA{R: 255, G: 210, B: 40, A: 255}, false)
+
+	// The title sits at the top of the toast and the description at its bottom
+	t.text.DrawIn(screen, current.title, box.Inset(8), titleStyle)
+	t.text.DrawIn(screen, current.description, box.Inset(8), descriptionStyle)
+}
@@ -1,77 +1,162 @@
 package background
 
 import (
+	"image/color"
 	"math/rand"
+	"slices"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/tejashwikalptaru/go.run/resources"
 	"github.com/tejashwikalptaru/go.run/resources/images"
 )
 
+const (
+	cloudSprites  = 4    // Procedural sprites drawn next to the cloud image
+	cloudDepths   = 3    // Far, middle and near clouds
+	minCloudSpeed = 0.2  // Drift of the farthest clouds in pixels per frame
+	maxCloudSpeed = 1.0  // Drift of the nearest clouds
+	defaultCloudY = 0.25 // Bottom of the cloud band as a share of the screen height
+)
+
 type cloudItem struct {
-	X     float64
-	Y     float64
-	Speed float64
+	sprite *ebiten.Image
+	x      float64
+	y      float64
+	depth  float64 // From 0 for the farthest clouds to 1 for the nearest
+}
+
+// scale returns how much the cloud is scaled, near clouds are bigger
+func (c cloudItem) scale() float64 {
+	return 0.5 + 0.6*c.depth
 }
 
-type Cloud struct {
-	rng           *rand.Rand
-	cloudImage    *ebiten.Image
-	clouds        []cloudItem
-	cloudWidth    float64
-	minCloudY     float64
-	maxCloudY     float64
-	minCloudSpeed float64
-	maxCloudSpeed float64
-	screenWidth   float64
-	screenHeight  float64
+// clouds is a sky layer of clouds drifting at different depths
+type clouds struct {
+	rng         *rand.Rand
+	sprites     []*ebiten.Image
+	items       []cloudItem // Sorted from far to near
+	count       int
+	minY        float64
+	maxY        float64
+	screenWidth float64
 }
 
-func NewCloud(screenWidth, screenHeight float64, rng *rand.Rand) (*Cloud, error) {
+func newClouds(def LayerDefinition, screenWidth, screenHeight float64) (*clouds, error) {
 	image, imageErr := resources.GetImage(images.Cloud)
 	if imageErr != nil {
 		return nil, imageErr
 	}
-	cloud := &Cloud{
-		cloudWidth:    100,
-		minCloudY:     5,
-		maxCloudY:     screenHeight / 4,
-		minCloudSpeed: 0.5,
-		maxCloudSpeed: 1.0,
-		screenWidth:   screenWidth,
-		screenHeight:  screenHeight,
-		rng:           rng,
-		cloudImage:    ebiten.NewImageFromImage(image),
+	rng := rand.New(rand.NewSource(def.Seed))
+	sprites := []*ebiten.Image{ebiten.NewImageFromImage(image)}
+	for i := 0; i < cloudSprites; i++ {
+		sprites = append(sprites, renderCloud(rng))
+	}
+
+	bandY := def.Y
+	if bandY == 0 {
+		bandY = defaultCloudY
 	}
-	for i := 0; i < 5; i++ {
-		cloud.addCloud()
+	c := &clouds{
+		rng:         rng,
+		sprites:     sprites,
+		count:       def.Count,
+		minY:        5,
+		maxY:        screenHeight * bandY,
+		screenWidth: screenWidth,
 	}
-	return cloud, nil
+	// Scatter the first clouds over the whole sky
+	for i := 0; i < c.count; i++ {
+		c.spawn(rng.Float64() * screenWidth)
+	}
+	return c, nil
 }
 
-// addCloud adds a new cloud at a random height and speed
-func (c *Cloud) addCloud() {
-	c.clouds = append(c.clouds, cloudItem{
-		X:     c.screenWidth,                                                       // Start the cloud on the right side of the screen
-		Y:     c.minCloudY + c.rng.Float64()*(c.maxCloudY-c.minCloudY),             // Restrict cloud YPosition position to upper part of screen
-		Speed: c.minCloudSpeed + c.rng.Float64()*(c.maxCloudSpeed-c.minCloudSpeed), // Random speed in the range
+// renderCloud draws a cloud of overlapping puffs with a shaded underside
+func renderCloud(rng *rand.Rand) *ebiten.Image {
+	width, height := 90+rng.Intn(50), 40+rng.Intn(16)
+	img := ebiten.NewImage(width, height)
+	shade := color.RGBA{R: 215, G: 222, B: 235, A: 255}
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	// Puffs are taller in the middle of the cloud
+	type puff struct{ x, y, radius float32 }
+	puffs := make([]puff, 4+rng.Intn(3))
+	base := float32(height) - 12
+	for i := range puffs {
+		middle := 1 - abs32(float32(i)/float32(len(puffs)-1)-0.5)
+		radius := 8 + middle*float32(height-20)*0.6 + rng.Float32()*4
+		puffs[i] = puff{x: 14 + float32(i)*float32(width-28)/float32(len(puffs)-1), y: base - radius/3, radius: radius}
+	}
+
+	// The shade peeks out under the white puffs
+	for _, pass := range []struct {
+		clr    color.RGBA
+		offset float32
+	}{{shade, 3}, {white, 0}} {
+		for _, p := range puffs {
+			vector.DrawFilledCircle(img, p.x, p.y+pass.offset, p.radius, pass.clr, true)
+		}
+		vector.DrawFilledRect(img, 14, base-4+pass.offset, float32(width-28), 8, pass.clr, true)
+	}
+	return img
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// spawn adds a cloud at x somewhere in the cloud band, every cloud enters the sky the same way
+func (c *clouds) spawn(x float64) {
+	depth := float64(c.rng.Intn(cloudDepths)) / (cloudDepths - 1)
+	depth = min(max(depth+(c.rng.Float64()-0.5)*0.2, 0), 1) // Spread the clouds within their depth
+	c.items = append(c.items, cloudItem{
+		sprite: c.sprites[c.rng.Intn(len(c.sprites))],
+		x:      x,
+		y:      c.minY + c.rng.Float64()*(c.maxY-c.minY),
+		depth:  depth,
+	})
+	slices.SortFunc(c.items, func(a, b cloudItem) int {
+		switch {
+		case a.depth < b.depth:
+			return -1
+		case a.depth > b.depth:
+			return 1
+		}
+		return 0
 	})
 }
 
-func (c *Cloud) Update() {
-	for i := range c.clouds {
-		c.clouds[i].X -= c.clouds[i].Speed // Move the cloud to the left
-		if c.clouds[i].X < -c.cloudWidth { // If the cloud goes off-screen
-			c.clouds[i].X = c.screenWidth                          // Reposition it to the right
-			c.clouds[i].Y = c.rng.Float64() * (c.screenHeight / 2) // Random height
+// update drifts the clouds and moves them with their share of the world speed, near clouds move faster
+func (c *clouds) update(worldSpeed float64) {
+	kept := c.items[:0]
+	for _, item := range c.items {
+		item.x -= (minCloudSpeed + (maxCloudSpeed-minCloudSpeed)*item.depth) + worldSpeed*item.depth
+		width := float64(item.sprite.Bounds().Dx()) * item.scale()
+		if item.x < -width {
+			continue
 		}
+		kept = append(kept, item)
+	}
+	c.items = kept
+
+	// Clouds that left the sky come back in from the right
+	for len(c.items) < c.count {
+		c.spawn(c.screenWidth)
 	}
 }
 
-func (c *Cloud) Draw(screen *ebiten.Image) {
-	for _, cloud := range c.clouds {
-		cloudOp := &ebiten.DrawImageOptions{}
-		cloudOp.GeoM.Translate(cloud.X, cloud.Y) // Translate the cloud to its position
-		screen.DrawImage(c.cloudImage, cloudOp)  // Draw the cloud
+// draw renders the clouds far to near through the view, far clouds are fainter
+func (c *clouds) draw(screen *ebiten.Image, view ebiten.GeoM) {
+	for _, item := range c.items {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(item.scale(), item.scale())
+		op.GeoM.Translate(item.x, item.y)
+		op.GeoM.Concat(view)
+		op.ColorScale.ScaleAlpha(float32(0.45 + 0.55*item.depth))
+		screen.DrawImage(item.sprite, op)
 	}
 }
@@ -0,0 +1,113 @@
+package background
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tejashwikalptaru/go.run/game/camera"
+)
+
+const (
+	dayLength = 240 * 60 // Frames from one dawn to the next
+	starCount = 60
+)
+
+// timeOfDay is a point of the cycle with the light at that time
+type timeOfDay struct {
+	at    float64    // Position in the cycle from 0 to 1
+	tint  [3]float32 // Color scale applied to the world
+	night float64    // How dark it is, it fades in the stars and the darkness
+}
+
+// timesOfDay are the points the light blends between, the last one leads back to the first
+var timesOfDay = []timeOfDay{
+	{at: 0, tint: [3]float32{1, 0.85, 0.8}, night: 0.2},     // Dawn
+	{at: 0.15, tint: [3]float32{1, 1, 1}, night: 0},         // Day
+	{at: 0.5, tint: [3]float32{1, 1, 1}, night: 0},          // Day
+	{at: 0.65, tint: [3]float32{1, 0.75, 0.55}, night: 0.1}, // Dusk
+	{at: 0.8, tint: [3]float32{0.35, 0.4, 0.65}, night: 1},  // Night
+	{at: 0.95, tint: [3]float32{0.35, 0.4, 0.65}, night: 1}, // Night
+	{at: 1, tint: [3]float32{1, 0.85, 0.8}, night: 0.2},     // Dawn
+}
+
+type starItem struct {
+	x, y    float32
+	size    float32
+	twinkle float64 // Phase of the twinkling
+}
+
+// DayCycle moves the time of day from dawn through dusk to night over the course of a run
+type DayCycle struct {
+	stars []starItem
+	ticks int
+}
+
+func NewDayCycle(screenWidth, screenHeight float64, rng *rand.Rand) *DayCycle {
+	stars := make([]starItem, starCount)
+	for i := range stars {
+		stars[i] = starItem{
+			x:       float32(rng.Float64() * screenWidth),
+			y:       float32(rng.Float64() * screenHeight * 0.5), // Stars only show in the upper half of the sky
+			size:    1 + float32(rng.Intn(2)),
+			twinkle: rng.Float64() * 2 * math.Pi,
+		}
+	}
+	return &DayCycle{stars: stars}
+}
+
+// Reset starts over at dawn
+func (d *DayCycle) Reset() {
+	d.ticks = 0
+}
+
+func (d *DayCycle) Update() {
+	d.ticks++
+}
+
+// current returns the light at the current time by blending the two closest times of day
+func (d *DayCycle) current() timeOfDay {
+	at := float64(d.ticks%dayLength) / dayLength
+	for i := 1; i < len(timesOfDay); i++ {
+		from, to := timesOfDay[i-1], timesOfDay[i]
+		if at > to.at {
+			continue
+		}
+		t := (at - from.at) / (to.at - from.at)
+		var tint [3]float32
+		for c := range tint {
+			tint[c] = from.tint[c] + (to.tint[c]-from.tint[c])*float32(t)
+		}
+		return timeOfDay{at: at, tint: tint, night: from.night + (to.night-from.night)*t}
+	}
+	return timesOfDay[0]
+}
+
+// Tint returns the color scale the world is drawn with at the current time
+func (d *DayCycle) Tint() ebiten.ColorScale {
+	tint := d.current().tint
+	var scale ebiten.ColorScale
+	scale.Scale(tint[0], tint[1], tint[2], 1)
+	return scale
+}
+
+// Night returns how dark it is, from 0 at day to 1 at night
+func (d *DayCycle) Night() float64 {
+	return d.current().night
+}
+
+// drawStars draws the stars through the view, fading in as the night falls
+func (d *DayCycle) drawStars(screen *ebiten.Image, view ebiten.GeoM) {
+	night := float32(d.Night())
+	if night <= 0 {
+		return
+	}
+	for _, s := range d.stars {
+		twinkle := 0.7 + 0.3*float32(math.Sin(float64(d.ticks)*0.05+s.twinkle))
+		v := uint8(255 * night * twinkle) // Premultiplied white
+		x, y, width, height := camera.Rect(view, float64(s.x), float64(s.y), float64(s.size), float64(s.size))
+		vector.DrawFilledRect(screen, x, y, width, height, color.RGBA{R: v, G: v, B: v, A: v}, false)
+	}
+}
@@ -0,0 +1,200 @@
+package background
+
+import (
+	"fmt"
+	"image/color"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	tileWidth    = 32
+	tileVariants = 4
+	propHeight   = 32 // Room above the ground for props
+)
+
+// PropKind is a decoration standing on the ground
+type PropKind string
+
+const (
+	PropKindRock   PropKind = "rock"
+	PropKindCactus PropKind = "cactus"
+	PropKindBones  PropKind = "bones"
+	PropKindTuft   PropKind = "tuft"
+)
+
+// GroundDefinition describes the tileset and decorations of a scene's ground strip
+type GroundDefinition struct {
+	Top       string     `json:"top"`    // Hex color of the surface
+	Body      string     `json:"body"`   // Hex color of the soil under the surface
+	Detail    string     `json:"detail"` // Hex color of the pebbles in the soil
+	Props     []PropKind `json:"props,omitempty"`
+	PropCount int        `json:"propCount,omitempty"`
+	Seed      int64      `json:"seed,omitempty"`
+}
+
+// validate checks the ground definition of the named scene
+func (d GroundDefinition) validate(scene string) error {
+	for _, hex := range []string{d.Top, d.Body, d.Detail} {
+		if _, colorErr := parseColor(hex); colorErr != nil {
+			return fmt.Errorf("scene %q ground: %w", scene, colorErr)
+		}
+	}
+	for _, kind := range d.Props {
+		if kind != PropKindRock && kind != PropKindCactus && kind != PropKindBones && kind != PropKindTuft {
+			return fmt.Errorf("scene %q ground has a prop of unknown kind %q", scene, kind)
+		}
+	}
+	if d.PropCount > 0 && len(d.Props) == 0 {
+		return fmt.Errorf("scene %q ground places props without any prop kinds", scene)
+	}
+	return nil
+}
+
+type propItem struct {
+	image *ebiten.Image
+	x     float64 // Position in the strip, it repeats along with the tiles
+}
+
+// ground is the strip the player runs on, it scrolls with the obstacles
+type ground struct {
+	strip   *ebiten.Image
+	props   []propItem
+	y       float64
+	width   float64
+	offsetX float64
+}
+
+func newGround(def GroundDefinition, screenWidth, groundY, groundHeight float64) *ground {
+	// The colors were checked when the definitions were loaded
+	top, _ := parseColor(def.Top)
+	body, _ := parseColor(def.Body)
+	detail, _ := parseColor(def.Detail)
+	rng := rand.New(rand.NewSource(def.Seed))
+
+	// A few tile variants laid out in a random order hide the repetition
+	tiles := make([]*ebiten.Image, tileVariants)
+	for i := range tiles {
+		tiles[i] = renderTile(rng, int(groundHeight), top, body, detail)
+	}
+	count := int(screenWidth) / tileWidth
+	strip := ebiten.NewImage(count*tileWidth, int(groundHeight))
+	for i := 0; i < count; i++ {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(i*tileWidth), 0)
+		strip.DrawImage(tiles[rng.Intn(len(tiles))], op)
+	}
+
+	g := &ground{
+		strip: strip,
+		y:     groundY,
+		width: float64(count * tileWidth),
+	}
+	for i := 0; i < def.PropCount; i++ {
+		g.props = append(g.props, propItem{
+			image: renderProp(rng, def.Props[rng.Intn(len(def.Props))], top, body),
+			x:     rng.Float64() * g.width,
+		})
+	}
+	return g
+}
+
+// renderTile draws a ground tile with a surface band and pebbles in the soil
+func renderTile(rng *rand.Rand, height int, top, body, detail color.RGBA) *ebiten.Image {
+	tile := ebiten.NewImage(tileWidth, height)
+	tile.Fill(body)
+	surface := 5 + rng.Intn(3)
+	vector.DrawFilledRect(tile, 0, 0, tileWidth, float32(surface), top, false)
+	vector.DrawFilledRect(tile, 0, float32(surface), tileWidth, 2, darken(top, 0.8), false)
+	for i := 0; i < 3+rng.Intn(3); i++ {
+		x := 2 + rng.Float32()*(tileWidth-4)
+		y := float32(surface+4) + rng.Float32()*float32(height-surface-6)
+		vector.DrawFilledRect(tile, x, y, 2+rng.Float32()*2, 2, detail, false)
+	}
+	return tile
+}
+
+// renderProp draws a decoration standing on the bottom edge of its image
+func renderProp(rng *rand.Rand, kind PropKind, top, body color.RGBA) *ebiten.Image {
+	img := ebiten.NewImage(propHeight, propHeight)
+	base := float32(propHeight)
+	switch kind {
+	case PropKindRock:
+		width := 12 + rng.Float32()*12
+		vector.DrawFilledCircle(img, propHeight/2, base, width/2, darken(body, 0.7), true)
+		vector.DrawFilledCircle(img, propHeight/2-width/6, base-width/6, width/5, darken(top, 0.9), true)
+	case PropKindCactus:
+		green := color.RGBA{R: 70, G: 120, B: 50, A: 255}
+		height := 18 + rng.Float32()*12
+		vector.StrokeLine(img, propHeight/2, base, propHeight/2, base-height, 5, green, true)
+		vector.StrokeLine(img, propHeight/2, base-height/2, propHeight/2-7, base-height/2, 3, green, true)
+		vector.StrokeLine(img, propHeight/2-7, base-height/2, propHeight/2-7, base-height*0.8, 3, green, true)
+		vector.StrokeLine(img, propHeight/2, base-height*0.6, propHeight/2+6, base-height*0.6, 3, green, true)
+		vector.StrokeLine(img, propHeight/2+6, base-height*0.6, propHeight/2+6, base-height*0.85, 3, green, true)
+	case PropKindBones:
+		bone := color.RGBA{R: 235, G: 228, B: 210, A: 255}
+		vector.StrokeLine(img, 6, base-3, propHeight-6, base-6, 2, bone, true)
+		vector.DrawFilledCircle(img, 6, base-3, 2.5, bone, true)
+		vector.DrawFilledCircle(img, propHeight-6, base-6, 2.5, bone, true)
+		vector.DrawFilledCircle(img, propHeight/2, base-8, 4, bone, true)
+	case PropKindTuft:
+		grass := color.RGBA{R: 120, G: 140, B: 60, A: 255}
+		for i := 0; i < 5; i++ {
+			dx := float32(i-2) * 3
+			vector.StrokeLine(img, propHeight/2+dx, base, propHeight/2+dx*2, base-8-rng.Float32()*6, 1.5, grass, true)
+		}
+	}
+	return img
+}
+
+// darken scales the color towards black
+func darken(clr color.RGBA, factor float64) color.RGBA {
+	return color.RGBA{
+		R: uint8(float64(clr.R) * factor),
+		G: uint8(float64(clr.G) * factor),
+		B: uint8(float64(clr.B) * factor),
+		A: clr.A,
+	}
+}
+
+// update scrolls the ground at the world speed
+func (g *ground) update(worldSpeed float64) {
+	g.offsetX -= worldSpeed
+	if g.offsetX <= -g.width {
+		g.offsetX += g.width
+	}
+}
+
+// draw renders the tiles and the props through the view, props above a pit are left out
+func (g *ground) draw(screen *ebiten.Image, screenWidth float64, gaps []Gap, view ebiten.GeoM) {
+	for x := g.offsetX; x < screenWidth; x += g.width {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(x, g.y)
+		op.GeoM.Concat(view)
+		screen.DrawImage(g.strip, op)
+	}
+
+	for _, p := range g.props {
+		for _, x := range []float64{p.x + g.offsetX, p.x + g.offsetX + g.width} {
+			if x > screenWidth || x+propHeight < 0 || overGap(x, propHeight, gaps) {
+				continue
+			}
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(x, g.y-propHeight)
+			op.GeoM.Concat(view)
+			screen.DrawImage(p.image, op)
+		}
+	}
+}
+
+// overGap returns true if the span from x overlaps one of the gaps
+func overGap(x, width float64, gaps []Gap) bool {
+	for _, gap := range gaps {
+		if x+width > gap.X && x < gap.X+gap.Width {
+			return true
+		}
+	}
+	return false
+}
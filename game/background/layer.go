@@ -0,0 +1,371 @@
+package background
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tejashwikalptaru/go.run/game/postfx"
+	"github.com/tejashwikalptaru/go.run/game/weather"
+	"github.com/tejashwikalptaru/go.run/resources"
+	"github.com/tejashwikalptaru/go.run/resources/images"
+)
+
+// LayerKind is how a background layer is drawn
+type LayerKind string
+
+const (
+	LayerKindSky    LayerKind = "sky"    // Vertical gradient from Top to Bottom
+	LayerKindImage  LayerKind = "image"  // One of the background images stretched over the screen
+	LayerKindHills  LayerKind = "hills"  // A ridge of hills filled down to the ground
+	LayerKindTrees  LayerKind = "trees"  // Palm tree silhouettes standing on the ground
+	LayerKindClouds LayerKind = "clouds" // Clouds drifting at different depths
+)
+
+// namedImages are the images a layer can refer to by name
+var namedImages = map[string][]byte{
+	"background-1": images.BackgroundOne,
+	"background-2": images.BackgroundTwo,
+	"background-3": images.BackgroundThree,
+	"background-4": images.BackgroundFour,
+}
+
+// LayerDefinition describes one layer of a scene
+type LayerDefinition struct {
+	Kind      LayerKind `json:"kind"`
+	Image     string    `json:"image,omitempty"`
+	Color     string    `json:"color,omitempty"`  // Hex color of hills and trees, like #a88a5e
+	Top       string    `json:"top,omitempty"`    // Hex color at the top of the sky
+	Bottom    string    `json:"bottom,omitempty"` // Hex color at the bottom of the sky
+	Speed     float64   `json:"speed"`            // Share of the world speed the layer scrolls at, 0 stands still and 1 moves with the ground
+	Y         float64   `json:"y,omitempty"`      // Height of the ridge, or the bottom of the cloud band, as a share of the screen height
+	Amplitude float64   `json:"amplitude,omitempty"`
+	Detail    int       `json:"detail,omitempty"` // Number of waves making up the ridge
+	Count     int       `json:"count,omitempty"`  // Number of trees or clouds
+	Seed      int64     `json:"seed,omitempty"`
+}
+
+// SceneDefinition is a background made of layers drawn back to front
+type SceneDefinition struct {
+	Name    string             `json:"name"`
+	Layers  []LayerDefinition  `json:"layers"`
+	Ground  GroundDefinition   `json:"ground"`
+	Weather *WeatherDefinition `json:"weather,omitempty"`
+	Grade   *GradeDefinition   `json:"grade,omitempty"`
+}
+
+// GradeDefinition describes the color grading of a scene, applied when the grading effect is on
+type GradeDefinition struct {
+	Tint       string  `json:"tint,omitempty"`       // Hex color the frame is multiplied with
+	Saturation float64 `json:"saturation,omitempty"` // 1 keeps the colors as they are, 1 when left out
+	Contrast   float64 `json:"contrast,omitempty"`   // 1 keeps the contrast as it is, 1 when left out
+}
+
+// grade converts the definition to the grade of the post-processing pipeline, the tint was checked when it was loaded
+func (d *GradeDefinition) grade() postfx.Grade {
+	grade := postfx.NeutralGrade
+	if d == nil {
+		return grade
+	}
+	if d.Tint != "" {
+		tint, _ := parseColor(d.Tint)
+		grade.Tint = [3]float32{float32(tint.R) / 255, float32(tint.G) / 255, float32(tint.B) / 255}
+	}
+	if d.Saturation != 0 {
+		grade.Saturation = float32(d.Saturation)
+	}
+	if d.Contrast != 0 {
+		grade.Contrast = float32(d.Contrast)
+	}
+	return grade
+}
+
+// WeatherDefinition describes the weather of a scene
+type WeatherDefinition struct {
+	Preset       weather.Preset `json:"preset"`
+	Intensity    float64        `json:"intensity,omitempty"`    // Scales the number of particles, 1 when left out
+	Wind         float64        `json:"wind,omitempty"`         // Pixels per frame, negative values blow towards the runner
+	AffectsJumps bool           `json:"affectsJumps,omitempty"` // The wind carries jumps further or cuts them short
+}
+
+// LoadScenes parses and validates the scene definitions
+func LoadScenes(data []byte) ([]SceneDefinition, error) {
+	var scenes []SceneDefinition
+	if unmarshalErr := json.Unmarshal(data, &scenes); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	if len(scenes) == 0 {
+		return nil, fmt.Errorf("no scenes defined")
+	}
+	for _, s := range scenes {
+		if len(s.Layers) == 0 {
+			return nil, fmt.Errorf("scene %q has no layers", s.Name)
+		}
+		if groundErr := s.Ground.validate(s.Name); groundErr != nil {
+			return nil, groundErr
+		}
+		if s.Weather != nil && s.Weather.Intensity < 0 {
+			return nil, fmt.Errorf("scene %q has weather with a negative intensity", s.Name)
+		}
+		if s.Grade != nil && s.Grade.Tint != "" {
+			if _, colorErr := parseColor(s.Grade.Tint); colorErr != nil {
+				return nil, fmt.Errorf("scene %q grade: %w", s.Name, colorErr)
+			}
+		}
+		if s.Grade != nil && (s.Grade.Saturation < 0 || s.Grade.Contrast < 0) {
+			return nil, fmt.Errorf("scene %q has a grade with a negative saturation or contrast", s.Name)
+		}
+		for _, l := range s.Layers {
+			switch {
+			case l.Kind == LayerKindImage && namedImages[l.Image] == nil:
+				return nil, fmt.Errorf("scene %q uses unknown image %q", s.Name, l.Image)
+			case l.Kind != LayerKindSky && l.Kind != LayerKindImage && l.Kind != LayerKindHills && l.Kind != LayerKindTrees && l.Kind != LayerKindClouds:
+				return nil, fmt.Errorf("scene %q has a layer of unknown kind %q", s.Name, l.Kind)
+			case l.Speed < 0:
+				return nil, fmt.Errorf("scene %q has a layer with a negative speed", s.Name)
+			case l.Count < 0:
+				return nil, fmt.Errorf("scene %q has a layer with a negative count", s.Name)
+			}
+		}
+	}
+	return scenes, nil
+}
+
+// parseColor parses a hex color like #a88a5e
+func parseColor(hex string) (color.RGBA, error) {
+	clr := color.RGBA{A: 255}
+	if _, scanErr := fmt.Sscanf(hex, "#%02x%02x%02x", &clr.R, &clr.G, &clr.B); scanErr != nil {
+		return clr, fmt.Errorf("invalid color %q: %w", hex, scanErr)
+	}
+	return clr, nil
+}
+
+// layer is a pre-rendered strip that repeats horizontally while it scrolls
+type layer struct {
+	kind    LayerKind
+	image   *ebiten.Image
+	clouds  *clouds // Set instead of the image for cloud layers
+	scaleX  float64
+	scaleY  float64
+	width   float64 // Width on screen after scaling
+	speed   float64
+	offsetX float64
+}
+
+func newLayer(def LayerDefinition, screenWidth, screenHeight, groundY float64) (*layer, error) {
+	if def.Kind == LayerKindClouds {
+		c, cloudsErr := newClouds(def, screenWidth, screenHeight)
+		if cloudsErr != nil {
+			return nil, cloudsErr
+		}
+		return &layer{kind: def.Kind, clouds: c, width: screenWidth, speed: def.Speed}, nil
+	}
+	if def.Kind == LayerKindImage {
+		img, imageErr := resources.GetImage(namedImages[def.Image])
+		if imageErr != nil {
+			return nil, imageErr
+		}
+		bgImg := ebiten.NewImageFromImage(img)
+		// Stretch the image to match the window size
+		return &layer{
+			kind:   def.Kind,
+			image:  bgImg,
+			scaleX: screenWidth / float64(bgImg.Bounds().Dx()),
+			scaleY: screenHeight / float64(bgImg.Bounds().Dy()),
+			width:  screenWidth,
+			speed:  def.Speed,
+		}, nil
+	}
+
+	img := ebiten.NewImage(int(screenWidth), int(screenHeight))
+	var renderErr error
+	switch def.Kind {
+	case LayerKindSky:
+		renderErr = renderSky(img, def)
+	case LayerKindHills:
+		renderErr = renderHills(img, def, groundY)
+	case LayerKindTrees:
+		renderErr = renderTrees(img, def, groundY)
+	}
+	if renderErr != nil {
+		return nil, renderErr
+	}
+	return &layer{
+		kind:   def.Kind,
+		image:  img,
+		scaleX: 1,
+		scaleY: 1,
+		width:  screenWidth,
+		speed:  def.Speed,
+	}, nil
+}
+
+// renderSky fills the image with a vertical gradient
+func renderSky(img *ebiten.Image, def LayerDefinition) error {
+	top, topErr := parseColor(def.Top)
+	if topErr != nil {
+		return topErr
+	}
+	bottom, bottomErr := parseColor(def.Bottom)
+	if bottomErr != nil {
+		return bottomErr
+	}
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	for y := 0; y < height; y++ {
+		t := float64(y) / float64(height-1)
+		clr := color.RGBA{
+			R: uint8(float64(top.R) + (float64(bottom.R)-float64(top.R))*t),
+			G: uint8(float64(top.G) + (float64(bottom.G)-float64(top.G))*t),
+			B: uint8(float64(top.B) + (float64(bottom.B)-float64(top.B))*t),
+			A: 255,
+		}
+		vector.DrawFilledRect(img, 0, float32(y), float32(width), 1, clr, false)
+	}
+	return nil
+}
+
+// renderHills draws a ridge made of waves that repeat exactly over the width of the image, so the layer tiles seamlessly
+func renderHills(img *ebiten.Image, def LayerDefinition, groundY float64) error {
+	clr, colorErr := parseColor(def.Color)
+	if colorErr != nil {
+		return colorErr
+	}
+	rng := rand.New(rand.NewSource(def.Seed))
+	width, height := float64(img.Bounds().Dx()), float64(img.Bounds().Dy())
+	baseY := height * def.Y
+
+	// Every wave completes a whole number of cycles across the width
+	type wave struct {
+		cycles, amplitude, phase float64
+	}
+	waves := make([]wave, max(def.Detail, 1))
+	for i := range waves {
+		waves[i] = wave{
+			cycles:    float64(i + 1 + rng.Intn(2)),
+			amplitude: def.Amplitude / float64(i+1),
+			phase:     rng.Float64() * 2 * math.Pi,
+		}
+	}
+
+	path := &vector.Path{}
+	path.MoveTo(0, float32(groundY))
+	for x := 0.0; x <= width; x += 4 {
+		y := baseY
+		for _, w := range waves {
+			y += w.amplitude * math.Sin(2*math.Pi*w.cycles*x/width+w.phase)
+		}
+		path.LineTo(float32(x), float32(y))
+	}
+	path.LineTo(float32(width), float32(groundY))
+	path.Close()
+	fillPath(img, path, clr)
+	return nil
+}
+
+// renderTrees draws palm trees at random spots, trees crossing an edge are drawn on both sides so the layer tiles seamlessly
+func renderTrees(img *ebiten.Image, def LayerDefinition, groundY float64) error {
+	clr, colorErr := parseColor(def.Color)
+	if colorErr != nil {
+		return colorErr
+	}
+	rng := rand.New(rand.NewSource(def.Seed))
+	width := float64(img.Bounds().Dx())
+	for i := 0; i < def.Count; i++ {
+		x := rng.Float64() * width
+		height := 60 + rng.Float64()*50
+		lean := (rng.Float64() - 0.5) * 30
+		for _, shift := range []float64{-width, 0, width} {
+			drawPalm(img, x+shift, groundY, height, lean, clr)
+		}
+	}
+	return nil
+}
+
+// drawPalm draws a palm tree silhouette with its foot at x, groundY
+func drawPalm(img *ebiten.Image, x, groundY, height, lean float64, clr color.RGBA) {
+	topX, topY := x+lean, groundY-height
+
+	// Slightly bent trunk
+	trunk := &vector.Path{}
+	trunk.MoveTo(float32(x), float32(groundY))
+	trunk.QuadTo(float32(x+lean*0.2), float32(groundY-height/2), float32(topX), float32(topY))
+	strokeOp := &vector.StrokeOptions{Width: 5, LineCap: vector.LineCapRound}
+	vertices, indices := trunk.AppendVerticesAndIndicesForStroke(nil, nil, strokeOp)
+	drawVertices(img, vertices, indices, clr)
+
+	// Drooping fronds
+	for i := 0; i < 6; i++ {
+		angle := math.Pi + float64(i)*math.Pi/5
+		length := height * 0.45
+		endX := topX + math.Cos(angle)*length
+		endY := topY + math.Sin(angle)*length*0.5 + length*0.35
+		frond := &vector.Path{}
+		frond.MoveTo(float32(topX), float32(topY))
+		frond.QuadTo(float32(topX+math.Cos(angle)*length*0.6), float32(topY-length*0.25), float32(endX), float32(endY))
+		vertices, indices = frond.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{Width: 4, LineCap: vector.LineCapRound})
+		drawVertices(img, vertices, indices, clr)
+	}
+}
+
+// fillPath fills a closed path with a solid color
+func fillPath(img *ebiten.Image, path *vector.Path, clr color.RGBA) {
+	vertices, indices := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	drawVertices(img, vertices, indices, clr)
+}
+
+// drawVertices draws triangles in a solid color
+func drawVertices(img *ebiten.Image, vertices []ebiten.Vertex, indices []uint16, clr color.RGBA) {
+	for i := range vertices {
+		vertices[i].SrcX = 1
+		vertices[i].SrcY = 1
+		vertices[i].ColorR = float32(clr.R) / 255
+		vertices[i].ColorG = float32(clr.G) / 255
+		vertices[i].ColorB = float32(clr.B) / 255
+		vertices[i].ColorA = 1
+	}
+	op := &ebiten.DrawTrianglesOptions{
+		FillRule:  ebiten.NonZero,
+		AntiAlias: true,
+	}
+	img.DrawTriangles(vertices, indices, whitePixel, op)
+}
+
+// whitePixel is the source image for solid color triangles, it is cut from the middle of a larger image to avoid bleeding at the edges
+var whitePixel = func() *ebiten.Image {
+	img := ebiten.NewImage(3, 3)
+	img.Fill(color.White)
+	return img.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+}()
+
+// update scrolls the layer at its share of the world speed
+func (l *layer) update(worldSpeed float64) {
+	if l.clouds != nil {
+		l.clouds.update(l.speed * worldSpeed)
+		return
+	}
+	l.offsetX -= l.speed * worldSpeed
+	// Wrap around for a seamless loop
+	if l.offsetX <= -l.width {
+		l.offsetX += l.width
+	}
+}
+
+// draw renders the layer twice through the view, the second copy fills the gap left by the scrolled first one
+func (l *layer) draw(screen *ebiten.Image, view ebiten.GeoM) {
+	if l.clouds != nil {
+		l.clouds.draw(screen, view)
+		return
+	}
+	for _, x := range []float64{l.offsetX, l.offsetX + l.width} {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(l.scaleX, l.scaleY)
+		op.GeoM.Translate(x, 0)
+		op.GeoM.Concat(view)
+		screen.DrawImage(l.image, op)
+	}
+}
@@ -1,67 +1,86 @@
 package background
 
 import (
+	"fmt"
 	"image/color"
+	"math/rand"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
-	"github.com/tejashwikalptaru/go.run/resources"
-	"github.com/tejashwikalptaru/go.run/resources/images"
+	"github.com/tejashwikalptaru/go.run/game/camera"
+	"github.com/tejashwikalptaru/go.run/game/postfx"
+	"github.com/tejashwikalptaru/go.run/game/weather"
 )
 
-type backgroundInfo struct {
-	image         *ebiten.Image
-	width, height int
+// Gap is a hole in the ground strip, like a pit
+type Gap struct {
+	X     float64
+	Width float64
+}
+
+// sceneItem is a rendered scene
+type sceneItem struct {
+	weather      *weather.Weather
+	layers       []*layer
+	ground       *ground
+	grade        postfx.Grade
+	affectsJumps bool
 }
 
 type Scene struct {
-	backGroundImage     *backgroundInfo
-	newBackGroundImage  *backgroundInfo
-	backgrounds         []backgroundInfo
-	screenWidth         float64
-	backgroundX         float64
-	backgroundSpeed     float64
-	groundY             float64
-	screenHeight        float64
-	backgroundIndex     int
-	groundHeight        float64
-	fadeAlpha           float32
-	fadeSpeed           float32
-	transitioning       bool
-	transitionCompleted bool
-}
-
-func NewScene(screenWidth, screenHeight int) (*Scene, error) {
-	// Load all background images and store them in a slice
-	bgImages := [][]byte{images.BackgroundOne, images.BackgroundTwo, images.BackgroundThree, images.BackgroundFour}
-	backgrounds := make([]backgroundInfo, len(bgImages))
-
-	for i, bg := range bgImages {
-		image, imageErr := resources.GetImage(bg)
-		if imageErr != nil {
-			return nil, imageErr
+	current      *sceneItem  // Scene on screen
+	previous     *sceneItem  // Scene still shown while the transition to the current one runs
+	scenes       []sceneItem // Every scene in the order they are played
+	dayCycle     *DayCycle
+	gaps         []Gap
+	screenWidth  float64
+	worldSpeed   float64
+	groundY      float64
+	screenHeight float64
+	sceneIndex   int
+	groundHeight float64
+}
+
+func NewScene(screenWidth, screenHeight int, definitions []SceneDefinition, dayCycle *DayCycle, rng *rand.Rand) (*Scene, error) {
+	groundHeight := 40.0
+	groundY := float64(screenHeight) - groundHeight
+
+	// Render the layers and the ground of every scene up front
+	scenes := make([]sceneItem, len(definitions))
+	for i, def := range definitions {
+		for _, layerDef := range def.Layers {
+			l, layerErr := newLayer(layerDef, float64(screenWidth), float64(screenHeight), groundY)
+			if layerErr != nil {
+				return nil, fmt.Errorf("scene %q: %w", def.Name, layerErr)
+			}
+			scenes[i].layers = append(scenes[i].layers, l)
 		}
-		bgImg := ebiten.NewImageFromImage(image)
-		backgrounds[i] = backgroundInfo{
-			image:  bgImg,
-			width:  bgImg.Bounds().Dx(),
-			height: bgImg.Bounds().Dy(),
+		scenes[i].ground = newGround(def.Ground, float64(screenWidth), groundY, groundHeight)
+		scenes[i].grade = def.Grade.grade()
+		if def.Weather != nil {
+			intensity := def.Weather.Intensity
+			if intensity == 0 {
+				intensity = 1
+			}
+			w, weatherErr := weather.New(def.Weather.Preset, intensity, def.Weather.Wind, float64(screenWidth), float64(screenHeight), rng)
+			if weatherErr != nil {
+				return nil, fmt.Errorf("scene %q: %w", def.Name, weatherErr)
+			}
+			scenes[i].weather = w
+			scenes[i].affectsJumps = def.Weather.AffectsJumps
 		}
 	}
 
-	groundHeight := 40.0
 	return &Scene{
-		groundHeight:    groundHeight,
-		groundY:         float64(screenHeight) - groundHeight,
-		backgroundX:     0.0,
-		backgroundSpeed: 1.0,
-		screenWidth:     float64(screenWidth),
-		screenHeight:    float64(screenHeight),
-		backGroundImage: &backgrounds[0],
-		backgrounds:     backgrounds,
-		backgroundIndex: 0,
-		fadeAlpha:       1.0,
-		fadeSpeed:       0.01,
+		groundHeight: groundHeight,
+		groundY:      groundY,
+		worldSpeed:   5.0,
+		screenWidth:  float64(screenWidth),
+		screenHeight: float64(screenHeight),
+		current:      &scenes[0],
+		scenes:       scenes,
+		dayCycle:     dayCycle,
+		sceneIndex:   0,
 	}, nil
 }
 
@@ -69,81 +88,136 @@ func (s *Scene) GroundY() float64 {
 	return s.groundY
 }
 
+// SetGaps sets the holes to cut out of the ground strip on the next draw
+func (s *Scene) SetGaps(gaps []Gap) {
+	s.gaps = gaps
+}
+
+// SetWorldSpeed sets the speed the ground scrolls at, every layer moves at its share of it
+func (s *Scene) SetWorldSpeed(speed float64) {
+	s.worldSpeed = speed
+}
+
+// JumpGravity returns the share of the gravity a jump feels in the wind of the current scene
+func (s *Scene) JumpGravity() float64 {
+	if s.current.weather == nil || !s.current.affectsJumps {
+		return 1
+	}
+	return s.current.weather.JumpGravity()
+}
+
+// Grade returns the color grading of the current scene
+func (s *Scene) Grade() postfx.Grade {
+	return s.current.grade
+}
+
+// DrawWeather renders the weather of the current scene, it is drawn in front of everything else in the world and straight
+// to the screen, the camera doesn't move it
+func (s *Scene) DrawWeather(screen *ebiten.Image) {
+	s.current.drawWeather(screen)
+}
+
+// DrawPreviousWeather renders the weather of the previous scene while the transition runs
+func (s *Scene) DrawPreviousWeather(screen *ebiten.Image) {
+	if s.previous != nil {
+		s.previous.drawWeather(screen)
+	}
+}
+
 func (s *Scene) Reset() {
-	s.backgroundIndex = 0
-	s.backGroundImage = &s.backgrounds[s.backgroundIndex]
+	s.gaps = nil
+	s.previous = nil
+	s.sceneIndex = 0
+	s.current = &s.scenes[s.sceneIndex]
 }
 
-// NextScene transitions to the next background image in the sequence
+// NextScene switches to the next scene in the sequence, the previous one keeps moving until FinishTransition
 func (s *Scene) NextScene() {
-	// Increment the background index
-	s.backgroundIndex = (s.backgroundIndex + 1) % len(s.backgrounds)
+	s.sceneIndex = (s.sceneIndex + 1) % len(s.scenes)
+	s.previous = s.current
+	s.current = &s.scenes[s.sceneIndex]
 
-	// Get the next background image and prepare the transition
-	s.newBackGroundImage = &s.backgrounds[s.backgroundIndex]
-	s.transitioning = true
-	s.transitionCompleted = false
-	s.fadeAlpha = 1.0 // Start fade-out process
+	// Carry the ground's position over so the strip doesn't jump
+	s.current.ground.offsetX = s.previous.ground.offsetX
+}
+
+// FinishTransition drops the previous scene once the transition is over
+func (s *Scene) FinishTransition() {
+	s.previous = nil
 }
 
 func (s *Scene) Update() {
-	// Handle the fading effect if transitioning to a new background
-	if s.transitioning {
-		if !s.transitionCompleted {
-			// Fade out the current background
-			s.fadeAlpha -= s.fadeSpeed
-			if s.fadeAlpha <= 0 {
-				// Fade out completed, mark as fully faded out
-				s.backGroundImage = s.newBackGroundImage
-				s.newBackGroundImage = nil
-				s.fadeAlpha = 0
-				s.transitionCompleted = true // Transition to new background
-			}
-		} else {
-			// Fade in the new background
-			s.fadeAlpha += s.fadeSpeed
-			if s.fadeAlpha >= 1 {
-				// Fade in completed, stop the transition
-				s.fadeAlpha = 1
-				s.transitioning = false // End transition
-			}
-		}
-		return
+	s.current.update(s.worldSpeed)
+	if s.previous != nil {
+		s.previous.update(s.worldSpeed)
 	}
+}
 
-	// Scroll the background to the left
-	s.backgroundX -= s.backgroundSpeed
+// Draw renders the current scene through the view of the camera
+func (s *Scene) Draw(screen *ebiten.Image, view ebiten.GeoM) {
+	s.draw(screen, s.current, view)
+}
+
+// DrawPrevious renders the previous scene while the transition runs
+func (s *Scene) DrawPrevious(screen *ebiten.Image, view ebiten.GeoM) {
+	if s.previous != nil {
+		s.draw(screen, s.previous, view)
+	}
+}
 
-	// Reset the background position for a seamless loop
-	if s.backgroundX <= -s.screenWidth {
-		s.backgroundX = 0
+// update scrolls the layers to the left, distant layers move slower
+func (item *sceneItem) update(worldSpeed float64) {
+	for _, l := range item.layers {
+		l.update(worldSpeed)
+	}
+	item.ground.update(worldSpeed)
+	if item.weather != nil {
+		item.weather.Update()
 	}
 }
 
-func (s *Scene) Draw(screen *ebiten.Image) {
-	// Ensure backGroundImage is not nil
-	if s.backGroundImage == nil {
-		return // Skip drawing if the background image isn't loaded
+func (item *sceneItem) drawWeather(screen *ebiten.Image) {
+	if item.weather != nil {
+		item.weather.Draw(screen)
 	}
+}
 
-	// Calculate scaling factors to resize the image to match the window size
-	scaleX := s.screenWidth / float64(s.backGroundImage.width)
-	scaleY := s.screenHeight / float64(s.backGroundImage.height)
-
-	// Draw the current background image with fade-out effect
-	op1 := &ebiten.DrawImageOptions{}
-	op1.GeoM.Scale(scaleX, scaleY)
-	op1.GeoM.Translate(s.backgroundX, 0)   // Apply translation for the scrolling effect
-	op1.ColorScale.ScaleAlpha(s.fadeAlpha) // Fade-out effect applied to current image
-	screen.DrawImage(s.backGroundImage.image, op1)
-
-	// Draw the second background image to create the seamless loop
-	op2 := &ebiten.DrawImageOptions{}
-	op2.GeoM.Scale(scaleX, scaleY)
-	op2.GeoM.Translate(s.backgroundX+s.screenWidth, 0) // Second image positioned right after the first
-	op2.ColorScale.ScaleAlpha(s.fadeAlpha)             // Fade-out effect applied to current image
-	screen.DrawImage(s.backGroundImage.image, op2)
-
-	// Draw base ground
-	vector.DrawFilledRect(screen, 0, float32(s.groundY), float32(s.screenWidth), float32(s.screenHeight), color.RGBA{R: 139, G: 69, B: 19, A: 255}, false)
+func (s *Scene) draw(screen *ebiten.Image, item *sceneItem, view ebiten.GeoM) {
+	// Draw the layers back to front, the stars come out right in front of the backdrop
+	stars := starsIndex(item.layers)
+	for i, l := range item.layers {
+		if i == stars {
+			s.dayCycle.drawStars(screen, view)
+		}
+		l.draw(screen, view)
+	}
+	if stars == len(item.layers) {
+		s.dayCycle.drawStars(screen, view)
+	}
+
+	// Draw the tiled ground with its props
+	item.ground.draw(screen, s.screenWidth, s.gaps, view)
+
+	// Cut the pits out of the ground, the walls get lighter towards the top
+	drawRect := func(x, y, width, height float64, clr color.RGBA) {
+		viewX, viewY, viewWidth, viewHeight := camera.Rect(view, x, y, width, height)
+		vector.DrawFilledRect(screen, viewX, viewY, viewWidth, viewHeight, clr, false)
+	}
+	for _, gap := range s.gaps {
+		drawRect(gap.X, s.groundY, gap.Width, s.groundHeight, color.RGBA{R: 30, G: 15, B: 5, A: 255})
+		drawRect(gap.X, s.groundY, gap.Width, 4, color.RGBA{R: 60, G: 35, B: 15, A: 255})
+		drawRect(gap.X, s.groundY, 3, s.groundHeight, color.RGBA{R: 50, G: 28, B: 12, A: 255})
+		drawRect(gap.X+gap.Width-3, s.groundY, 3, s.groundHeight, color.RGBA{R: 50, G: 28, B: 12, A: 255})
+	}
+}
+
+// starsIndex returns the index of the layer the stars are drawn behind, the backdrop is the sky or an image opening the
+// scene, the image covers the whole screen and paints its own sky so the stars are drawn over it
+func starsIndex(layers []*layer) int {
+	for i, l := range layers {
+		if l.kind != LayerKindSky && (i > 0 || l.kind != LayerKindImage) {
+			return i
+		}
+	}
+	return len(layers)
 }
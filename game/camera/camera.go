@@ -0,0 +1,156 @@
+package camera
+
+import (
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Camera looks at the world, it shakes on impacts, freezes for a few frames on hits and punches in on level clears
+type Camera struct {
+	rng           *rand.Rand
+	screenWidth   float64
+	screenHeight  float64
+	shakeStrength float64 // Largest offset in pixels at the start of the shake
+	shakeFrames   int
+	shakeLength   int
+	offsetX       float64
+	offsetY       float64
+	freezeFrames  int
+	punchZoom     float64 // Extra zoom at the peak of the punch
+	punchFrames   int
+	punchLength   int
+	focusX        float64 // Point the punch zooms in on
+	focusY        float64
+	reducedMotion bool
+}
+
+func NewCamera(screenWidth, screenHeight float64, rng *rand.Rand) *Camera {
+	return &Camera{
+		rng:          rng,
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+	}
+}
+
+// SetReducedMotion turns shakes, freezes and punches off, the camera holds still
+func (c *Camera) SetReducedMotion(reduced bool) {
+	c.reducedMotion = reduced
+	if reduced {
+		c.Reset()
+	}
+}
+
+// Reset stops every effect
+func (c *Camera) Reset() {
+	c.shakeFrames = 0
+	c.freezeFrames = 0
+	c.punchFrames = 0
+	c.offsetX, c.offsetY = 0, 0
+}
+
+// Shake shakes the camera, the shake fades out over the frames and a weaker shake doesn't cut a stronger one short
+func (c *Camera) Shake(strength float64, frames int) {
+	if c.reducedMotion || (c.shakeFrames > 0 && c.shakeStrength*float64(c.shakeFrames)/float64(c.shakeLength) > strength) {
+		return
+	}
+	c.shakeStrength = strength
+	c.shakeFrames = frames
+	c.shakeLength = frames
+}
+
+// HitStop freezes the world for a few frames to make an impact land
+func (c *Camera) HitStop(frames int) {
+	if c.reducedMotion {
+		return
+	}
+	c.freezeFrames = max(c.freezeFrames, frames)
+}
+
+// Punch zooms in on a point and back out over the frames
+func (c *Camera) Punch(zoom float64, frames int, focusX, focusY float64) {
+	if c.reducedMotion {
+		return
+	}
+	c.punchZoom = zoom
+	c.punchFrames = frames
+	c.punchLength = frames
+	c.focusX, c.focusY = focusX, focusY
+}
+
+// Frozen returns true while a hit-stop holds the world still
+func (c *Camera) Frozen() bool {
+	return c.freezeFrames > 0
+}
+
+func (c *Camera) Update() {
+	if c.freezeFrames > 0 {
+		c.freezeFrames--
+	}
+	if c.punchFrames > 0 {
+		c.punchFrames--
+	}
+	c.offsetX, c.offsetY = 0, 0
+	if c.shakeFrames > 0 {
+		c.shakeFrames--
+		strength := c.shakeStrength * float64(c.shakeFrames) / float64(c.shakeLength)
+		c.offsetX = (c.rng.Float64()*2 - 1) * strength
+		c.offsetY = (c.rng.Float64()*2 - 1) * strength
+	}
+}
+
+// zoom returns how far the camera is zoomed in, it zooms in a little further while shaking so the edges never show
+func (c *Camera) zoom() float64 {
+	zoom := 1.0
+	if c.punchFrames > 0 {
+		// Zoom in fast and ease back out
+		t := float64(c.punchFrames) / float64(c.punchLength)
+		zoom += c.punchZoom * t * t
+	}
+	if c.shakeFrames > 0 {
+		zoom *= 1 + 2*c.shakeStrength/min(c.screenWidth, c.screenHeight)
+	}
+	return zoom
+}
+
+// GeoM returns the transform from world to screen coordinates
+func (c *Camera) GeoM() ebiten.GeoM {
+	zoom := c.zoom()
+	focusX, focusY := c.screenWidth/2, c.screenHeight/2
+	if c.punchFrames > 0 {
+		focusX, focusY = c.focusX, c.focusY
+	}
+
+	// Keep the world covering the whole screen
+	x := min(max(focusX-zoom*focusX+c.offsetX, c.screenWidth-zoom*c.screenWidth), 0)
+	y := min(max(focusY-zoom*focusY+c.offsetY, c.screenHeight-zoom*c.screenHeight), 0)
+
+	var geoM ebiten.GeoM
+	geoM.Scale(zoom, zoom)
+	geoM.Translate(x, y)
+	return geoM
+}
+
+// Transform returns where a point of the world ends up on screen
+func (c *Camera) Transform(x, y float64) (float64, float64) {
+	geoM := c.GeoM()
+	return geoM.Apply(x, y)
+}
+
+// Scale returns how much larger the world is drawn through the view, the camera zooms the same in both directions
+func Scale(view ebiten.GeoM) float64 {
+	return view.Element(0, 0)
+}
+
+// Rect returns where a rectangle of the world is drawn through the view, in the arguments of the vector package
+func Rect(view ebiten.GeoM, x, y, width, height float64) (float32, float32, float32, float32) {
+	left, top := view.Apply(x, y)
+	right, bottom := view.Apply(x+width, y+height)
+	return float32(left), float32(top), float32(right - left), float32(bottom - top)
+}
+
+// Circle returns where a circle of the world is drawn through the view, in the arguments of the vector package
+func Circle(view ebiten.GeoM, x, y, radius float64) (float32, float32, float32) {
+	centerX, centerY := view.Apply(x, y)
+	return float32(centerX), float32(centerY), float32(radius * Scale(view))
+}
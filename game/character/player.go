@@ -9,17 +9,33 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/vector"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/tejashwikalptaru/go.run/game/music"
+	"github.com/tejashwikalptaru/go.run/game/background"
+	"github.com/tejashwikalptaru/go.run/game/camera"
+	"github.com/tejashwikalptaru/go.run/game/event"
 	"github.com/tejashwikalptaru/go.run/resources/sprites"
 )
 
+// immuneFrames is how long the player can't be hurt after losing a life or a shield
+const immuneFrames = 90
+
+const (
+	pitType      = "pit" // Collision type published when the player falls into a pit
+	pitDepth     = 30    // How far the player sinks into a pit before it counts as a fall
+	jumpVelocity = 12    // Upward speed at the start of a jump
+)
+
 type Player struct {
+	spriteSheet      image.Image
 	sprite           *ebiten.Image
-	musicManager     *music.Manager
+	skins            map[string]*ebiten.Image
+	events           *event.Bus
+	pits             []background.Gap
 	xPosition        float64
 	frameDelay       int
 	collisionWidth   float64
 	gravity          float64
+	jumpGravity      float64 // Share of the gravity felt in the current wind
+	autopilot        func() bool
 	groundY          float64
 	scaleFactor      float64
 	collisionTop     float64
@@ -34,25 +50,32 @@ type Player struct {
 	frameIndex       int
 	collisionHeight  float64
 	frameCount       int
+	immuneFrames     int
 	height           float64
 	screenWidth      float64
 	walkingToExit    bool
 	isJumping        bool
+	inPit            bool
+	hasShield        bool
 	debug            bool
 }
 
-func NewPlayer(screenWidth, groundY float64, musicManager *music.Manager, debug bool) (*Player, error) {
+func NewPlayer(screenWidth, groundY float64, events *event.Bus, debug bool) (*Player, error) {
 	height := 64.0
 	// Load the player sprite sheet (runner animation)
 	img, _, err := image.Decode(bytes.NewReader(sprites.Runner))
 	if err != nil {
 		return nil, err
 	}
+	sprite := ebiten.NewImageFromImage(img)
 	return &Player{
+		spriteSheet:      img,
+		skins:            map[string]*ebiten.Image{SkinClassic: sprite},
 		height:           height,
 		width:            64,
 		velocityY:        0,
 		gravity:          0.6,
+		jumpGravity:      1,
 		groundY:          groundY,
 		yPosition:        groundY - height,
 		xPosition:        -70,
@@ -63,14 +86,14 @@ func NewPlayer(screenWidth, groundY float64, musicManager *music.Manager, debug
 		frameIndex:       0,
 		frameDelay:       5, // Animation speed
 		frameCount:       0,
-		sprite:           ebiten.NewImageFromImage(img),
+		sprite:           sprite,
 		scaleFactor:      2.0,
 		collisionTop:     10,
 		collisionLeft:    20,
 		collisionWidth:   25,
 		collisionHeight:  55,
 		walkingToExit:    false,
-		musicManager:     musicManager,
+		events:           events,
 		screenWidth:      screenWidth,
 		debug:            debug,
 	}, nil
@@ -84,6 +107,10 @@ func (p *Player) Height() float64 {
 	return p.height
 }
 
+func (p *Player) XPosition() float64 {
+	return p.xPosition
+}
+
 func (p *Player) YPosition() float64 {
 	return p.yPosition
 }
@@ -108,8 +135,59 @@ func (p *Player) ScaleFactor() float64 {
 	return p.scaleFactor
 }
 
+// IsFalling returns true while the player is coming down from a jump
+func (p *Player) IsFalling() bool {
+	return p.isJumping && p.velocityY > 0
+}
+
+// Bounce throws the player back up in the air, like after landing on an enemy
+func (p *Player) Bounce() {
+	p.velocityY = -9
+	p.isJumping = true
+}
+
 func (p *Player) IsImmune() bool {
-	return false
+	return p.immuneFrames > 0
+}
+
+// MakeImmune protects the player from getting hurt for a short while
+func (p *Player) MakeImmune() {
+	p.immuneFrames = immuneFrames
+}
+
+// ImmuneLeft returns the share of the immunity that is left, from 1 right after a hit to 0
+func (p *Player) ImmuneLeft() float64 {
+	return float64(p.immuneFrames) / immuneFrames
+}
+
+// HasShield returns true while the player's shield is up
+func (p *Player) HasShield() bool {
+	return p.hasShield
+}
+
+// SetAutopilot lets the function decide when to jump instead of the keyboard, nil hands control back
+func (p *Player) SetAutopilot(jump func() bool) {
+	p.autopilot = jump
+}
+
+// AirTime returns the frames a jump from the ground takes to land again
+func (p *Player) AirTime() float64 {
+	return 2 * jumpVelocity / (p.gravity * p.jumpGravity)
+}
+
+// SetShield gives or takes away the player's shield
+func (p *Player) SetShield(shield bool) {
+	p.hasShield = shield
+}
+
+// UseShield breaks the shield to absorb a hit, it returns false if the player has no shield
+func (p *Player) UseShield() bool {
+	if !p.hasShield {
+		return false
+	}
+	p.hasShield = false
+	p.MakeImmune()
+	return true
 }
 
 // Reset function brings the player back to the ground and resets jumping state.
@@ -117,9 +195,37 @@ func (p *Player) Reset() {
 	p.xPosition = -70
 	p.yPosition = p.groundY - p.height
 	p.isJumping = false
+	p.inPit = false
+	p.pits = nil
 	p.velocityY = 0
 	p.frameIndex = 0
 	p.frameCount = 0
+	p.immuneFrames = 0
+}
+
+// SetJumpGravity sets the share of the gravity felt during a jump, wind makes jumps floatier or heavier
+func (p *Player) SetJumpGravity(share float64) {
+	p.jumpGravity = share
+}
+
+// SetPits sets the holes in the ground the player can fall into
+func (p *Player) SetPits(pits []background.Gap) {
+	p.pits = pits
+}
+
+// overPit returns true if the player's feet are completely over a pit, an immune player runs across pits
+func (p *Player) overPit() bool {
+	if p.immuneFrames > 0 {
+		return false
+	}
+	left := p.xPosition + p.collisionLeft
+	right := left + p.collisionWidth
+	for _, pit := range p.pits {
+		if left > pit.X && right < pit.X+pit.Width {
+			return true
+		}
+	}
+	return false
 }
 
 func (p *Player) WalkingToLevelExit() bool {
@@ -145,10 +251,14 @@ func (p *Player) WalkingToLevelExit() bool {
 }
 
 func (p *Player) Update() {
-	if ebiten.IsKeyPressed(ebiten.KeySpace) && !p.isJumping {
-		p.velocityY = -12
+	wantsJump := ebiten.IsKeyPressed(ebiten.KeySpace)
+	if p.autopilot != nil {
+		wantsJump = p.autopilot()
+	}
+	if wantsJump && !p.isJumping {
+		p.velocityY = -jumpVelocity
 		p.isJumping = true
-		p.musicManager.PlayJumpSound()
+		event.Publish(p.events, event.Jumped{})
 	}
 
 	// walk the player in
@@ -156,19 +266,43 @@ func (p *Player) Update() {
 		p.xPosition++
 	}
 
+	// Running over a pit makes the player drop into it
+	if !p.isJumping && p.overPit() {
+		p.isJumping = true
+		p.inPit = true
+	}
+
 	// Apply gravity and update player's position
 	if p.isJumping {
 		p.yPosition += p.velocityY
-		p.velocityY += p.gravity
+		p.velocityY += p.gravity * p.jumpGravity
 
-		// Stop falling when reaching the ground
-		if p.yPosition >= p.groundY-p.height {
+		switch {
+		case p.inPit && p.yPosition >= p.groundY-p.height+pitDepth:
+			// Climb back out, losing a life is up to the game
+			p.yPosition = p.groundY - p.height
+			p.isJumping = false
+			p.inPit = false
+			p.velocityY = 0
+			event.Publish(p.events, event.Collision{Source: event.SourceHazard, Type: pitType})
+		case p.inPit:
+			// Keep falling into the pit
+		case p.yPosition >= p.groundY-p.height && p.overPit():
+			// Landing over a pit misses the ground
+			p.inPit = true
+		case p.yPosition >= p.groundY-p.height:
+			// Stop falling when reaching the ground
 			p.yPosition = p.groundY - p.height
 			p.isJumping = false
 			p.velocityY = 0
+			event.Publish(p.events, event.Landed{})
 		}
 	}
 
+	if p.immuneFrames > 0 {
+		p.immuneFrames--
+	}
+
 	// Update animation frame (cycle through the runner frames)
 	p.frameCount++
 	if p.frameCount >= p.frameDelay {
@@ -180,7 +314,8 @@ func (p *Player) Update() {
 	}
 }
 
-func (p *Player) Draw(screen *ebiten.Image) {
+// Draw renders the runner through the view of the camera
+func (p *Player) Draw(screen *ebiten.Image, view ebiten.GeoM) {
 	// Calculate the frame position on the sprite sheet
 	sx := p.frameIndex * p.frameWidth
 
@@ -194,9 +329,19 @@ func (p *Player) Draw(screen *ebiten.Image) {
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Scale(p.scaleFactor, p.scaleFactor) // Scale the sprite to make it larger
 	op.GeoM.Translate(p.xPosition, p.yPosition) // Position the sprite at the player's position
+	op.GeoM.Concat(view)                        // Look at it through the camera
+	if p.immuneFrames > 0 && (p.immuneFrames/4)%2 == 0 {
+		op.ColorScale.ScaleAlpha(0.3) // Flicker while immune
+	}
 	// Draw the sprite using the current frame
 	screen.DrawImage(subImage, op)
 
+	if p.hasShield {
+		// Draw the shield as a bubble around the player
+		x, y, radius := camera.Circle(view, p.xPosition+p.width/2, p.yPosition+p.height/2, p.height/2+4)
+		vector.StrokeCircle(screen, x, y, radius, float32(2*camera.Scale(view)), color.RGBA{R: 80, G: 180, B: 255, A: 200}, true)
+	}
+
 	if p.debug {
 		// Visualise the collision box for debugging
 		collisionTop := p.collisionTop
@@ -220,13 +365,14 @@ func (p *Player) Draw(screen *ebiten.Image) {
 		}
 
 		// Draw the player's collision rectangle
+		x, y, width, height := camera.Rect(view, p.xPosition+collisionLeft, p.yPosition+collisionTop, collisionWidth, collisionHeight)
 		vector.DrawFilledRect(
 			screen,
-			float32(p.xPosition+collisionLeft), // X position with collision offset
-			float32(p.yPosition+collisionTop),  // Y position with collision offset
-			float32(collisionWidth),            // Scaled collision width
-			float32(collisionHeight),           // Scaled collision height
-			color.RGBA{R: 255, A: 128},         // Color of the rectangle (Red with 50% transparency)
+			x,                          // X position with collision offset
+			y,                          // Y position with collision offset
+			width,                      // Scaled collision width
+			height,                     // Scaled collision height
+			color.RGBA{R: 255, A: 128}, // Color of the rectangle (Red with 50% transparency)
 			false,
 		)
 	}
@@ -0,0 +1,132 @@
+package character
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Skins are palette swapped variants of the runner sprite
+const (
+	SkinClassic = "classic"
+	SkinCrimson = "crimson"
+	SkinForest  = "forest"
+	SkinOcean   = "ocean"
+	SkinShadow  = "shadow"
+	SkinGold    = "gold"
+)
+
+// palette maps a color of the original sprite to the color used by a skin
+type palette func(r, g, b float64) (float64, float64, float64)
+
+var skinPalettes = map[string]palette{
+	SkinCrimson: hueShift(150),
+	SkinForest:  hueShift(90),
+	SkinOcean:   hueShift(210),
+	SkinShadow: func(r, g, b float64) (float64, float64, float64) {
+		// Dark grey, keeping the original brightness differences
+		l := (0.3*r + 0.59*g + 0.11*b) * 0.45
+		return l, l, l * 1.1
+	},
+	SkinGold: func(r, g, b float64) (float64, float64, float64) {
+		// Golden sepia tone
+		l := 0.3*r + 0.59*g + 0.11*b
+		return l * 1.2, l * 0.95, l * 0.45
+	},
+}
+
+// hueShift returns a palette that rotates the hue of every color by the given degrees
+func hueShift(degrees float64) palette {
+	return func(r, g, b float64) (float64, float64, float64) {
+		h, s, v := rgbToHSV(r, g, b)
+		return hsvToRGB(math.Mod(h+degrees, 360), s, v)
+	}
+}
+
+// rgbToHSV converts a color with channels in the range 0-1 to hue (0-360), saturation and value
+func rgbToHSV(r, g, b float64) (h, s, v float64) {
+	maxC := max(r, g, b)
+	minC := min(r, g, b)
+	delta := maxC - minC
+	v = maxC
+	if maxC > 0 {
+		s = delta / maxC
+	}
+	switch {
+	case delta == 0:
+		h = 0
+	case maxC == r:
+		h = 60 * math.Mod((g-b)/delta, 6)
+	case maxC == g:
+		h = 60 * ((b-r)/delta + 2)
+	default:
+		h = 60 * ((r-g)/delta + 4)
+	}
+	if h < 0 {
+		h += 360
+	}
+	return h, s, v
+}
+
+// hsvToRGB converts hue (0-360), saturation and value back to a color with channels in the range 0-1
+func hsvToRGB(h, s, v float64) (r, g, b float64) {
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - c
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return r + m, g + m, b + m
+}
+
+// applyPalette creates a copy of the sprite sheet with every pixel mapped through the palette
+func applyPalette(src image.Image, p palette) *image.NRGBA {
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(src.At(x, y)).(color.NRGBA)
+			if c.A == 0 {
+				continue // Leave transparent pixels alone
+			}
+			r, g, b := p(float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(math.Min(r, 1) * 255),
+				G: uint8(math.Min(g, 1) * 255),
+				B: uint8(math.Min(b, 1) * 255),
+				A: c.A,
+			})
+		}
+	}
+	return dst
+}
+
+// SetSkin switches the runner sprite to the given skin, unknown skins fall back to the classic look
+func (p *Player) SetSkin(skin string) {
+	if sprite, ok := p.skins[skin]; ok {
+		p.sprite = sprite
+		return
+	}
+	skinPalette, ok := skinPalettes[skin]
+	if !ok {
+		p.sprite = p.skins[SkinClassic]
+		return
+	}
+	// Palette swap the sprite sheet once and keep it for the next time the skin is used
+	sprite := ebiten.NewImageFromImage(applyPalette(p.spriteSheet, skinPalette))
+	p.skins[skin] = sprite
+	p.sprite = sprite
+}
@@ -0,0 +1,31 @@
+package game
+
+// startDemo starts an endless demo run behind the title screen, it is practice so nothing in it counts
+func (g *Game) startDemo() error {
+	g.demo = true
+	g.endless = true // No bosses to get stuck at
+	g.setPractice(true)
+	g.Player.SetAutopilot(g.demoJump)
+	return g.ResetGame()
+}
+
+// stopDemo ends the demo run and sets up a fresh run behind the title screen
+func (g *Game) stopDemo() error {
+	g.demo = false
+	g.endless = false
+	g.Player.SetAutopilot(nil)
+	g.setPractice(false)
+	return g.ResetGame()
+}
+
+// demoJump decides when the demo runner jumps, it takes off so that the middle of its jump is over the next obstacle
+func (g *Game) demoJump() bool {
+	left, right, found := g.Obstacle.Ahead(g.Player.XPosition())
+	if !found {
+		return false
+	}
+	runnerCenter := g.Player.XPosition() + g.Player.Width()/2
+	distance := (left+right)/2 - runnerCenter
+	reach := g.Obstacle.Speed() * g.Player.AirTime() // Ground covered while in the air
+	return distance > 0 && distance <= reach/2
+}
@@ -0,0 +1,110 @@
+package display
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tejashwikalptaru/go.run/game/profile"
+)
+
+// Display draws the game at its own resolution and scales it to the window, keeping the aspect ratio
+type Display struct {
+	canvas       *ebiten.Image // The game is drawn to this at its own resolution
+	profile      *profile.Profile
+	width        int
+	height       int
+	appliedScale int // Window scale the window was last sized to
+}
+
+func NewDisplay(width, height int, profile *profile.Profile) *Display {
+	d := &Display{
+		canvas:  ebiten.NewImage(width, height),
+		profile: profile,
+		width:   width,
+		height:  height,
+	}
+	d.apply()
+	return d
+}
+
+// SetProfile switches to the settings of another profile
+func (d *Display) SetProfile(profile *profile.Profile) {
+	d.profile = profile
+	d.apply()
+}
+
+// apply sizes the window and enters or leaves fullscreen to match the settings
+func (d *Display) apply() {
+	settings := d.profile.Settings
+	if scale := d.fittingScale(settings.Scale()); scale != d.appliedScale {
+		ebiten.SetWindowSize(d.width*scale, d.height*scale)
+		d.appliedScale = scale
+	}
+	if ebiten.IsFullscreen() != settings.Fullscreen {
+		ebiten.SetFullscreen(settings.Fullscreen)
+	}
+}
+
+// fittingScale returns the largest window scale up to the requested one that fits on the monitor
+func (d *Display) fittingScale(scale int) int {
+	monitor := ebiten.Monitor()
+	if monitor == nil {
+		return scale
+	}
+	monitorWidth, monitorHeight := monitor.Size()
+	if monitorWidth == 0 || monitorHeight == 0 {
+		return scale
+	}
+	for scale > 1 && (d.width*scale > monitorWidth || d.height*scale > monitorHeight) {
+		scale--
+	}
+	return scale
+}
+
+// Update toggles fullscreen with F11 and follows changes made to the settings
+func (d *Display) Update() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyF11) {
+		d.profile.Settings.Fullscreen = !d.profile.Settings.Fullscreen
+		if saveErr := d.profile.Save(); saveErr != nil {
+			fmt.Printf("failed to save profile: %v", saveErr)
+		}
+	}
+	d.apply()
+}
+
+// Canvas returns the image the game is drawn to, it is cleared every frame
+func (d *Display) Canvas() *ebiten.Image {
+	return d.canvas
+}
+
+// Layout returns the size of the screen in device pixels, the canvas is scaled to it in Draw
+func (d *Display) Layout(outsideWidth, outsideHeight float64) (float64, float64) {
+	scaleFactor := 1.0
+	if monitor := ebiten.Monitor(); monitor != nil {
+		scaleFactor = monitor.DeviceScaleFactor()
+	}
+	return outsideWidth * scaleFactor, outsideHeight * scaleFactor
+}
+
+// Draw scales the canvas to the screen and fills the bars left over with black
+func (d *Display) Draw(screen *ebiten.Image) {
+	screen.Fill(color.Black)
+	screenWidth, screenHeight := float64(screen.Bounds().Dx()), float64(screen.Bounds().Dy())
+	scale := math.Min(screenWidth/float64(d.width), screenHeight/float64(d.height))
+
+	op := &ebiten.DrawImageOptions{}
+	if d.profile.Settings.PixelPerfect && scale >= 1 {
+		// Whole multiples keep every pixel of the sprites the same size
+		scale = math.Floor(scale)
+		op.Filter = ebiten.FilterNearest
+	} else {
+		op.Filter = ebiten.FilterLinear
+	}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(math.Floor((screenWidth-float64(d.width)*scale)/2), math.Floor((screenHeight-float64(d.height)*scale)/2))
+	screen.DrawImage(d.canvas, op)
+	d.canvas.Clear()
+}
@@ -1,39 +1,114 @@
 package game
 
 import (
-	"fmt"
-	"image/color"
-
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/text/v2"
-	"github.com/tejashwikalptaru/go.run/resources/fonts"
+	"github.com/tejashwikalptaru/go.run/game/hud"
 )
 
-// Draw renders the game screen
+// darknessRadius is how far the runner can see when it is dark
+const darknessRadius = 220
+
+// Draw renders the game at its own resolution and scales it to the screen
 func (g *Game) Draw(screen *ebiten.Image) {
-	g.Scene.Draw(screen)
-	g.Cloud.Draw(screen)
+	g.finished.Clear()
+	g.drawFrame(g.finished)
+	g.PostFX.Apply(g.Display.Canvas(), g.finished, g.Profile.Settings.Effects, g.Scene.Grade())
+	g.Display.Draw(screen)
+}
+
+// drawFrame renders the game screen
+func (g *Game) drawFrame(screen *ebiten.Image) {
+	g.drawWorld(screen)
+	if g.Title.IsOpen() {
+		g.Title.Draw(screen)
+		g.Achievements.Draw(screen)
+		return
+	}
+	if g.Tutorial.Active() {
+		g.Popups.Draw(screen)
+		g.Tutorial.Draw(screen)
+		g.Achievements.Draw(screen)
+		return
+	}
 	g.Level.Draw(screen)
-	g.Obstacle.Draw(screen)
-	g.Player.Draw(screen)
+	g.Popups.Draw(screen)
 
 	// If game over, display message
 	if g.GameOver {
-		msg := fmt.Sprintf("GAME OVER\n\nScore: %d\nPress Space to Restart", g.Level.Score())
-		op := &text.DrawOptions{
-			LayoutOptions: text.LayoutOptions{
-				LineSpacing:  50,
-				PrimaryAlign: text.AlignCenter,
-			},
-		}
-		op.GeoM.Translate(ScreenWidth/2, ScreenHeight/6)
-		op.ColorScale.ScaleWithColor(color.RGBA{R: 255, A: 255})
-		text.Draw(screen, msg, &text.GoTextFace{
-			Source: g.TextFaceSource,
-			Size:   fonts.DefaultTextSize,
-		}, op)
+		g.Summary.Draw(screen, g.Stats.Run(), g.Profile.Coins)
+	} else {
+		g.HUD.Draw(screen, hud.State{
+			Score:      g.Level.Score(),
+			Multiplier: g.Level.Multiplier(),
+			Level:      g.Level.Number(),
+			Progress:   g.Level.Progress(),
+			Boss:       g.Level.HasBoss() && !g.endless,
+			Speed:      g.Obstacle.Speed(),
+			Lives:      g.Lives,
+			Coins:      g.Coins.Collected(),
+			Shield:     g.Player.HasShield(),
+			ImmuneLeft: g.Player.ImmuneLeft(),
+		})
+	}
+
+	if g.Shop.IsOpen() {
+		g.Shop.Draw(screen)
+	}
+	g.Achievements.Draw(screen)
+}
+
+// drawWorld renders the lit world, while a transition runs the previous scene gives way to the current one
+func (g *Game) drawWorld(screen *ebiten.Image) {
+	if !g.Transition.Active() {
+		g.composeWorld(screen, false)
+		return
+	}
+	g.composeWorld(g.frame, false)
+	g.composeWorld(g.previousFrame, true)
+	centerX, centerY := g.Camera.Transform(g.Player.XPosition()+g.Player.Width()/2, g.Player.YPosition()+g.Player.Height()/2)
+	g.Transition.Draw(screen, g.previousFrame, g.frame, centerX, centerY)
+}
+
+// composeWorld renders the scene and everything in it to the frame, as the camera sees it and lit by the time of day
+func (g *Game) composeWorld(frame *ebiten.Image, previous bool) {
+	g.world.Clear()
+	view := g.Camera.GeoM() // Every layer looks through the camera on its own, the weather stays on the screen
+	if previous {
+		g.Scene.DrawPrevious(g.world, view)
 	} else {
-		ebitenutil.DebugPrint(screen, fmt.Sprintf("Score: %d", g.Level.Score()))
+		g.Scene.Draw(g.world, view)
+	}
+	if !g.Title.IsOpen() || g.demo {
+		g.Coins.Draw(g.world, view)
+		g.Obstacle.Draw(g.world, view)
+		g.Boss.Draw(g.world, view)
+		g.Player.Draw(g.world, view)
+	}
+	if previous {
+		g.Scene.DrawPreviousWeather(g.world)
+	} else {
+		g.Scene.DrawWeather(g.world)
+	}
+
+	frame.Clear()
+	tint := g.DayCycle.Tint()
+	night := g.DayCycle.Night()
+	if !g.Profile.Settings.NightDarkness || night <= 0 || g.Title.IsOpen() {
+		op := &ebiten.DrawImageOptions{}
+		op.ColorScale = tint
+		frame.DrawImage(g.world, op)
+		return
+	}
+
+	// Only the runner's surroundings stay lit at night
+	centerX, centerY := g.Camera.Transform(g.Player.XPosition()+g.Player.Width()/2, g.Player.YPosition()+g.Player.Height()/2)
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Images[0] = g.world
+	op.ColorScale = tint
+	op.Uniforms = map[string]any{
+		"Center":   []float32{float32(centerX), float32(centerY)},
+		"Radius":   float32(darknessRadius),
+		"Darkness": float32(night * 0.85),
 	}
+	frame.DrawRectShader(ScreenWidth, ScreenHeight, g.darkness, op)
 }
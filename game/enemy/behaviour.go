@@ -0,0 +1,140 @@
+package enemy
+
+import (
+	"math"
+	"math/rand"
+)
+
+// behaviour decides how an obstacle moves every frame
+type behaviour interface {
+	update(obs *obstacleItem, playerX float64)
+}
+
+// newBehaviour returns the movement behaviour for the given obstacle type
+func newBehaviour(t obstacleType, rng *rand.Rand, screenWidth float64) behaviour {
+	switch t {
+	case obstacleTypeVulture:
+		return &swoopBehaviour{
+			dropX:     screenWidth - 150,
+			amplitude: 40,
+			frequency: 0.05,
+			phase:     rng.Float64() * 2 * math.Pi, // Start each vulture at a different point of the swoop
+		}
+	case obstacleTypeHyena:
+		return &sprintBehaviour{
+			triggerDistance: 250,
+			boost:           1.6,
+		}
+	case obstacleTypeScorpio:
+		return &pauseBehaviour{
+			pauseX:      screenWidth / 2,
+			pauseFrames: 30,
+			slowdown:    0.5,
+		}
+	case obstacleTypeMummy:
+		return &hopBehaviour{
+			period:    60,
+			hopFrames: 24,
+			height:    25,
+		}
+	default:
+		return walkBehaviour{}
+	}
+}
+
+// walkBehaviour moves the obstacle left at a constant speed along a fixed Y
+type walkBehaviour struct{}
+
+func (walkBehaviour) update(obs *obstacleItem, _ float64) {
+	obs.xPosition -= obs.speed
+}
+
+// swoopBehaviour moves a flying obstacle along a sine path around its base height
+type swoopBehaviour struct {
+	dropX     float64
+	amplitude float64
+	frequency float64
+	phase     float64
+	dropped   bool
+}
+
+func (b *swoopBehaviour) update(obs *obstacleItem, _ float64) {
+	obs.xPosition -= obs.speed
+	obs.yPosition = obs.baseY + b.amplitude*math.Sin(b.phase+float64(obs.ticks)*b.frequency)
+}
+
+// fire drops a single rock once the obstacle has flown onto the screen
+func (b *swoopBehaviour) fire(obs *obstacleItem, _ float64) (projectileItem, bool) {
+	if b.dropped || obs.xPosition > b.dropX {
+		return projectileItem{}, false
+	}
+	b.dropped = true
+	return newRock(obs.xPosition+obs.width/2, obs.yPosition+obs.height, obs.speed*1.6), true
+}
+
+// sprintBehaviour speeds the obstacle up once it gets close to the player
+type sprintBehaviour struct {
+	triggerDistance float64
+	boost           float64
+}
+
+func (b *sprintBehaviour) update(obs *obstacleItem, playerX float64) {
+	speed := obs.speed
+	if obs.xPosition > playerX && obs.xPosition-playerX < b.triggerDistance {
+		speed *= b.boost // Close enough, sprint towards the player
+	}
+	obs.xPosition -= speed
+}
+
+// pauseBehaviour slows the obstacle down for a while once it reaches the middle of the screen
+type pauseBehaviour struct {
+	pauseX      float64
+	pauseFrames int
+	remaining   int
+	slowdown    float64
+	paused      bool
+	spat        bool
+}
+
+func (b *pauseBehaviour) update(obs *obstacleItem, playerX float64) {
+	// Start the pause once, when the obstacle reaches the middle of the screen
+	if !b.paused && obs.xPosition < b.pauseX && obs.xPosition > playerX {
+		b.paused = true
+		b.remaining = b.pauseFrames
+	}
+
+	speed := obs.speed
+	if b.remaining > 0 {
+		speed *= b.slowdown
+		b.remaining--
+	}
+	obs.xPosition -= speed
+}
+
+// fire spits venom along the ground once the pause has started
+func (b *pauseBehaviour) fire(obs *obstacleItem, groundY float64) (projectileItem, bool) {
+	if !b.paused || b.spat {
+		return projectileItem{}, false
+	}
+	b.spat = true
+	return newVenom(obs.xPosition, groundY, obs.speed*2), true
+}
+
+// hopBehaviour makes a ground obstacle hop periodically while walking
+type hopBehaviour struct {
+	period    int
+	hopFrames int
+	height    float64
+}
+
+func (b *hopBehaviour) update(obs *obstacleItem, _ float64) {
+	obs.xPosition -= obs.speed
+
+	// Hop during the first part of every period and walk on the ground for the rest
+	step := obs.ticks % b.period
+	if step < b.hopFrames {
+		obs.yPosition = obs.baseY - b.height*math.Sin(math.Pi*float64(step)/float64(b.hopFrames))
+	} else {
+		obs.yPosition = obs.baseY
+	}
+}
@@ -0,0 +1,366 @@
+package enemy
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tejashwikalptaru/go.run/game/camera"
+	"github.com/tejashwikalptaru/go.run/game/character"
+	"github.com/tejashwikalptaru/go.run/game/event"
+	"github.com/tejashwikalptaru/go.run/resources"
+	"github.com/tejashwikalptaru/go.run/resources/sprites"
+)
+
+type bossState int
+
+const (
+	bossStateIdle bossState = iota
+	bossStateEntering
+	bossStateWaiting
+	bossStateTelegraph
+	bossStateAttacking
+	bossStateReturning
+	bossStateDefeated
+)
+
+type bossAttack string
+
+const (
+	bossAttackVolley bossAttack = "volley"
+	bossAttackRocks  bossAttack = "rocks"
+	bossAttackCharge bossAttack = "charge"
+)
+
+const (
+	bossScaleFactor     = 2.5
+	bossWaitFrames      = 60
+	bossTelegraphFrames = 45
+	bossShotDelay       = 40
+	bossHurtFrames      = 60
+	bossDefeatFrames    = 90
+	bossSurviveFrames   = 30 * 60 // Survive for 30 seconds to win without stomping
+	bossStompDepth      = 20      // How deep into the boss' head the player's feet may sink to count as a stomp
+)
+
+// Boss is a large multi-phase enemy fought at the end of boss levels
+type Boss struct {
+	rng             *rand.Rand
+	player          *character.Player
+	events          *event.Bus
+	frames          []*ebiten.Image
+	projectiles     []projectileItem
+	attack          bossAttack
+	xPosition       float64
+	yPosition       float64
+	homeX           float64
+	screenWidth     float64
+	groundY         float64
+	width           float64
+	height          float64
+	collisionTop    float64
+	collisionLeft   float64
+	collisionWidth  float64
+	collisionHeight float64
+	speed           float64
+	state           bossState
+	health          int
+	maxHealth       int
+	timer           int
+	surviveFrames   int
+	shotsLeft       int
+	shotDelay       int
+	hurtFrames      int
+	frameIndex      int
+	frameCount      int
+	frameDelay      int
+	debug           bool
+}
+
+func NewBoss(screenWidth, groundY float64, player *character.Player, events *event.Bus, rng *rand.Rand, debug bool) (*Boss, error) {
+	mummy, mummyErr := resources.GetImage(sprites.MummyWalk)
+	if mummyErr != nil {
+		return nil, mummyErr
+	}
+	height := obstacleSpriteSize * bossScaleFactor
+	return &Boss{
+		rng:             rng,
+		player:          player,
+		events:          events,
+		frames:          loadFrames(ebiten.NewImageFromImage(mummy), 48, 48, 6, debug),
+		screenWidth:     screenWidth,
+		groundY:         groundY,
+		homeX:           screenWidth - 200,
+		width:           obstacleSpriteSize * bossScaleFactor,
+		height:          height,
+		yPosition:       groundY - height,
+		collisionTop:    20,
+		collisionLeft:   58,
+		collisionWidth:  50,
+		collisionHeight: 100,
+		frameDelay:      8,
+		state:           bossStateIdle,
+		debug:           debug,
+	}, nil
+}
+
+// Start brings the boss on screen, a higher strength gives it more health and faster attacks
+func (b *Boss) Start(strength int) {
+	b.maxHealth = 2 + strength
+	b.health = b.maxHealth
+	b.speed = 5 + float64(strength)
+	b.surviveFrames = bossSurviveFrames
+	b.xPosition = b.screenWidth + 50
+	b.yPosition = b.groundY - b.height
+	b.projectiles = nil
+	b.hurtFrames = 0
+	b.state = bossStateEntering
+}
+
+// Reset puts the boss away until it is started again
+func (b *Boss) Reset() {
+	b.state = bossStateIdle
+	b.projectiles = nil
+}
+
+// Active returns true while the boss fight is running
+func (b *Boss) Active() bool {
+	return b.state != bossStateIdle
+}
+
+// Defeated returns true once the boss has been beaten and has left the screen
+func (b *Boss) Defeated() bool {
+	return b.state == bossStateDefeated && b.timer <= 0
+}
+
+// phase returns the current fight phase, the boss gets more aggressive as it loses health
+func (b *Boss) phase() int {
+	return min(b.maxHealth-b.health+1, 3)
+}
+
+// wait makes the boss stand still at its home position before the next attack
+func (b *Boss) wait() {
+	b.state = bossStateWaiting
+	b.timer = bossWaitFrames
+}
+
+// chooseAttack picks the next attack from the ones available in the current phase
+func (b *Boss) chooseAttack() {
+	attacks := []bossAttack{bossAttackVolley, bossAttackCharge}
+	if b.phase() >= 2 {
+		attacks = append(attacks, bossAttackRocks)
+	}
+	b.attack = attacks[b.rng.Intn(len(attacks))]
+	b.state = bossStateTelegraph
+	b.timer = bossTelegraphFrames
+}
+
+// startAttack sets up the chosen attack once the telegraph is over
+func (b *Boss) startAttack() {
+	b.state = bossStateAttacking
+	b.shotDelay = 0
+	switch b.attack {
+	case bossAttackVolley:
+		b.shotsLeft = 2 + b.phase()
+	case bossAttackRocks:
+		b.shotsLeft = 1 + b.phase()
+	default:
+		b.shotsLeft = 0
+	}
+}
+
+// throwRock throws a rock in an arc so that it lands in front of the player and rolls into them
+func (b *Boss) throwRock() projectileItem {
+	rock := newRock(b.xPosition+b.width/3, b.yPosition+b.height/3, 0)
+	rock.velocityY = -6
+
+	// Solve the flight time until the rock touches the ground and pick the horizontal speed from it
+	drop := b.groundY - rock.radius - rock.yPosition
+	flightTime := (-rock.velocityY + math.Sqrt(rock.velocityY*rock.velocityY+2*rock.gravity*drop)) / rock.gravity
+	target := b.player.XPosition() + 150 + b.rng.Float64()*100
+	rock.velocityX = (rock.xPosition - target) / flightTime
+	return rock
+}
+
+// updateAttack runs one frame of the current attack
+func (b *Boss) updateAttack() {
+	if b.attack == bossAttackCharge {
+		// Dash across the screen, the player has to jump on the boss' head
+		b.xPosition -= b.speed * 1.5
+		if b.xPosition < -b.width {
+			b.xPosition = b.screenWidth + 50
+			b.state = bossStateReturning
+		}
+		return
+	}
+
+	b.shotDelay--
+	if b.shotDelay > 0 {
+		return
+	}
+	if b.shotsLeft == 0 {
+		b.wait()
+		return
+	}
+	if b.attack == bossAttackVolley {
+		b.projectiles = append(b.projectiles, newVenom(b.xPosition+b.collisionLeft, b.groundY, b.speed*1.5))
+	} else {
+		b.projectiles = append(b.projectiles, b.throwRock())
+	}
+	b.shotsLeft--
+	b.shotDelay = bossShotDelay
+}
+
+// defeat ends the fight
+func (b *Boss) defeat() {
+	b.state = bossStateDefeated
+	b.timer = bossDefeatFrames
+	b.projectiles = nil
+}
+
+// checkPlayer checks if the player stomped the boss or ran into it
+func (b *Boss) checkPlayer() (stomped, collision bool) {
+	// Player's collision boundaries
+	playerLeft := 40 + b.player.CollisionLeft()
+	playerRight := playerLeft + b.player.CollisionWidth()
+	playerTop := b.player.YPosition() + b.player.CollisionTop()
+	playerBottom := playerTop + b.player.CollisionHeight()
+
+	// Boss' collision boundaries
+	bossLeft := b.xPosition + b.collisionLeft
+	bossRight := bossLeft + b.collisionWidth
+	bossTop := b.yPosition + b.collisionTop
+	bossBottom := bossTop + b.collisionHeight
+
+	xOverlap := playerRight > bossLeft && playerLeft < bossRight
+	if !xOverlap {
+		return false, false
+	}
+
+	// The head is the weak point, landing on it hurts the boss
+	if b.player.IsFalling() && playerBottom >= bossTop && playerBottom <= bossTop+bossStompDepth {
+		return true, false
+	}
+	return false, playerBottom > bossTop && playerTop < bossBottom
+}
+
+// Update runs the boss fight and publishes a collision when the boss or its projectiles hit the player
+func (b *Boss) Update() {
+	if b.state == bossStateIdle {
+		return
+	}
+
+	// Animate the boss
+	b.frameCount++
+	if b.frameCount >= b.frameDelay {
+		b.frameIndex = (b.frameIndex + 1) % len(b.frames)
+		b.frameCount = 0
+	}
+
+	if b.state == bossStateDefeated {
+		// Sink into the ground
+		b.yPosition += 1.5
+		b.timer--
+		return
+	}
+
+	// Surviving long enough wins the fight as well
+	b.surviveFrames--
+	if b.surviveFrames <= 0 {
+		b.defeat()
+		return
+	}
+
+	switch b.state {
+	case bossStateEntering, bossStateReturning:
+		b.xPosition -= 2
+		if b.xPosition <= b.homeX {
+			b.xPosition = b.homeX
+			b.wait()
+		}
+	case bossStateWaiting:
+		b.timer--
+		if b.timer <= 0 {
+			b.chooseAttack()
+		}
+	case bossStateTelegraph:
+		b.timer--
+		if b.timer <= 0 {
+			b.startAttack()
+		}
+	case bossStateAttacking:
+		b.updateAttack()
+	}
+
+	b.projectiles = moveProjectiles(b.projectiles, b.groundY)
+	if !b.player.IsImmune() {
+		for _, p := range b.projectiles {
+			if projectileCollision(b.player, &p) {
+				event.Publish(b.events, event.Collision{Source: event.SourceProjectile, Type: string(p.projectileType)})
+				return
+			}
+		}
+	}
+
+	// The boss can't be hurt and doesn't hurt while recovering from a stomp
+	if b.hurtFrames > 0 {
+		b.hurtFrames--
+		return
+	}
+	stomped, collision := b.checkPlayer()
+	if stomped {
+		b.health--
+		b.hurtFrames = bossHurtFrames
+		b.player.Bounce()
+		if b.health <= 0 {
+			b.defeat()
+		}
+		return
+	}
+	if collision && !b.player.IsImmune() {
+		event.Publish(b.events, event.Collision{Source: event.SourceBoss, Type: "boss"})
+	}
+}
+
+// Draw renders the boss and its projectiles through the view of the camera, the health bar stays put on the screen
+func (b *Boss) Draw(screen *ebiten.Image, view ebiten.GeoM) {
+	if b.state == bossStateIdle {
+		return
+	}
+	drawProjectiles(screen, b.projectiles, view)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(bossScaleFactor, bossScaleFactor)
+	op.GeoM.Translate(b.xPosition, b.yPosition)
+	op.GeoM.Concat(view)
+	switch {
+	case b.state == bossStateTelegraph && (b.timer/5)%2 == 0:
+		op.ColorScale.Scale(1, 0.3, 0.3, 1) // Flash red to warn the player about the next attack
+	case b.hurtFrames > 0 && (b.hurtFrames/4)%2 == 0:
+		op.ColorScale.ScaleAlpha(0.3) // Flicker while recovering from a stomp
+	case b.state == bossStateDefeated:
+		op.ColorScale.ScaleAlpha(float32(b.timer) / bossDefeatFrames)
+	}
+	screen.DrawImage(b.frames[b.frameIndex], op)
+
+	if b.state == bossStateDefeated {
+		return
+	}
+
+	// Health bar with the remaining survival time underneath
+	barWidth := float32(200)
+	barX := float32(b.screenWidth)/2 - barWidth/2
+	vector.DrawFilledRect(screen, barX, 10, barWidth, 12, color.RGBA{R: 60, G: 0, B: 0, A: 200}, false)
+	vector.DrawFilledRect(screen, barX, 10, barWidth*float32(b.health)/float32(b.maxHealth), 12, color.RGBA{R: 220, G: 20, B: 20, A: 255}, false)
+	vector.DrawFilledRect(screen, barX, 24, barWidth*float32(b.surviveFrames)/bossSurviveFrames, 4, color.RGBA{R: 255, G: 255, B: 255, A: 200}, false)
+
+	if b.debug {
+		// Visualise the collision box and the weak point for debugging
+		x, y, width, height := camera.Rect(view, b.xPosition+b.collisionLeft, b.yPosition+b.collisionTop, b.collisionWidth, b.collisionHeight)
+		vector.DrawFilledRect(screen, x, y, width, height, color.RGBA{R: 255, A: 128}, false)
+		x, y, width, height = camera.Rect(view, b.xPosition+b.collisionLeft, b.yPosition+b.collisionTop, b.collisionWidth, bossStompDepth)
+		vector.DrawFilledRect(screen, x, y, width, height, color.RGBA{G: 255, A: 128}, false)
+	}
+}
@@ -0,0 +1,81 @@
+package enemy
+
+import "github.com/tejashwikalptaru/go.run/game/background"
+
+type hazardType string
+
+const (
+	hazardTypePit hazardType = "pit"
+)
+
+// pitRunUp is the room needed on both sides of a pit to jump over it
+const pitRunUp = 100.0
+
+type hazardItem struct {
+	hazardType hazardType
+	xPosition  float64
+	width      float64
+	speed      float64
+}
+
+// forcedPitGap returns the smallest gap after an obstacle of the width that fits a pit of the layout and the run-up on both sides
+func (o *Obstacle) forcedPitGap(obstacleWidth float64) float64 {
+	return obstacleWidth + o.layout.PitWidth + 2*pitRunUp
+}
+
+// placeHazard digs a pit in the middle of a gap between two obstacles, forced pits come from the level layout and are always
+// dug, Prepare widens their gaps to fit them, other pits are placed by chance in wide gaps
+func (o *Obstacle) placeHazard(gapStart, gapEnd float64, forced bool) {
+	width := o.layout.PitWidth
+	if !forced && (gapEnd-gapStart < o.minHazardGap || o.rng.Float64() > o.layout.PitChance) {
+		return
+	}
+	o.hazards = append(o.hazards, hazardItem{
+		hazardType: hazardTypePit,
+		xPosition:  gapStart + (gapEnd-gapStart-width)/2,
+		width:      width,
+		speed:      o.obstacleSpeed,
+	})
+}
+
+// updateHazards moves the hazards along with the ground and removes the ones that left the screen
+func (o *Obstacle) updateHazards() {
+	var filtered []hazardItem
+	for _, h := range o.hazards {
+		h.xPosition -= h.speed
+		if h.xPosition > -h.width {
+			filtered = append(filtered, h)
+		}
+	}
+	o.hazards = filtered
+}
+
+// Pits returns the gaps cut out of the ground strip, the player falls into them
+func (o *Obstacle) Pits() []background.Gap {
+	gaps := make([]background.Gap, 0, len(o.hazards))
+	for _, h := range o.hazards {
+		if h.hazardType == hazardTypePit {
+			gaps = append(gaps, background.Gap{X: h.xPosition, Width: h.width})
+		}
+	}
+	return gaps
+}
+
+// Ahead returns the left and right edges of the nearest obstacle or pit that is not yet behind x, power-ups are left out
+func (o *Obstacle) Ahead(x float64) (float64, float64, bool) {
+	left, right, found := 0.0, 0.0, false
+	consider := func(l, r float64) {
+		if r > x && (!found || l < left) {
+			left, right, found = l, r, true
+		}
+	}
+	for _, obs := range o.obstacles {
+		if !obs.isPowerUpObject {
+			consider(obs.xPosition, obs.xPosition+obs.width)
+		}
+	}
+	for _, gap := range o.Pits() {
+		consider(gap.X, gap.X+gap.Width)
+	}
+	return left, right, found
+}
@@ -0,0 +1,70 @@
+package enemy
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tejashwikalptaru/go.run/game/pickup"
+)
+
+// newTestObstacle returns an obstacle without sprites or coins that lays out the track of the layout
+func newTestObstacle(seed int64, layout LevelLayout) *Obstacle {
+	o := &Obstacle{
+		rng:            rand.New(rand.NewSource(seed)),
+		coins:          &pickup.Coins{},
+		obstacleImages: make(map[obstacleType]obstacleSpriteInfo),
+		layouts:        []LevelLayout{layout},
+		layout:         layout,
+		minObstacleGap: 250,
+		maxObstacleGap: 400,
+		minHazardGap:   300,
+		screenWidth:    800,
+		obstacleSpeed:  5,
+		groundY:        300,
+		scaleFactor:    1.5,
+		maxObstacles:   50,
+	}
+	for _, t := range []obstacleType{obstacleTypeSnake, obstacleTypeHyena, obstacleTypeScorpio, obstacleTypeVulture, obstacleTypeMummy, obstacleTypeDeceased} {
+		o.obstacleImages[t] = obstacleSpriteInfo{width: obstacleSpriteSize, height: obstacleSpriteSize}
+	}
+	return o
+}
+
+func TestPrepareDigsForcedPits(t *testing.T) {
+	layouts := []LevelLayout{
+		{Pits: []int{10, 30}, PitWidth: 50},
+		{Pits: []int{5, 20, 35}, PitWidth: 55},
+		{Pits: []int{4, 12, 20, 28, 40}, PitWidth: 60},
+		{Pits: []int{1, 2, 3, 49}, PitWidth: 120},
+	}
+	for _, layout := range layouts {
+		for seed := int64(0); seed < 50; seed++ {
+			o := newTestObstacle(seed, layout)
+			o.Prepare()
+
+			for _, after := range layout.Pits {
+				// The pit after obstacle n lies between it and the obstacle after it
+				start := o.obstacles[after-1].xPosition + o.obstacles[after-1].width
+				end := o.obstacles[after].xPosition
+				found := false
+				for _, h := range o.hazards {
+					const tolerance = 1e-6
+					if h.xPosition >= start+pitRunUp-tolerance && h.xPosition+h.width <= end-pitRunUp+tolerance {
+						found = true
+					}
+				}
+				if !found {
+					t.Fatalf("layout %v, seed %d: no pit with its run-up after obstacle %d", layout.Pits, seed, after)
+				}
+			}
+		}
+	}
+}
+
+func TestPrepareLeavesPitsOutWithoutChance(t *testing.T) {
+	o := newTestObstacle(1, LevelLayout{PitWidth: 50})
+	o.Prepare()
+	if len(o.hazards) != 0 {
+		t.Fatalf("got %d pits in a layout without pits and no pit chance", len(o.hazards))
+	}
+}
@@ -0,0 +1,33 @@
+package enemy
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LevelLayout describes the ground of a level, levels past the last layout reuse it
+type LevelLayout struct {
+	Pits      []int   `json:"pits,omitempty"` // A pit is always dug after these obstacles, counting from 1
+	PitChance float64 `json:"pitChance"`      // Chance of a pit in any other wide gap
+	PitWidth  float64 `json:"pitWidth"`
+}
+
+// LoadLayouts parses and validates the level layouts
+func LoadLayouts(data []byte) ([]LevelLayout, error) {
+	var layouts []LevelLayout
+	if unmarshalErr := json.Unmarshal(data, &layouts); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	if len(layouts) == 0 {
+		return nil, fmt.Errorf("no level layouts defined")
+	}
+	for i, l := range layouts {
+		switch {
+		case l.PitWidth <= 0:
+			return nil, fmt.Errorf("level %d needs a pit width above zero", i+1)
+		case l.PitChance < 0 || l.PitChance > 1:
+			return nil, fmt.Errorf("level %d has a pit chance outside 0 to 1", i+1)
+		}
+	}
+	return layouts, nil
+}
@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"math"
 	"math/rand"
+	"slices"
 
 	"github.com/hajimehoshi/ebiten/v2/vector"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/tejashwikalptaru/go.run/game/camera"
 	"github.com/tejashwikalptaru/go.run/game/character"
+	"github.com/tejashwikalptaru/go.run/game/event"
+	"github.com/tejashwikalptaru/go.run/game/pickup"
 	"github.com/tejashwikalptaru/go.run/resources"
 	"github.com/tejashwikalptaru/go.run/resources/sprites"
 )
@@ -27,6 +32,9 @@ const (
 
 const obstacleSpriteSize = 48
 
+// closeCallMargin is the largest gap in pixels between the player and an obstacle that counts as a close call
+const closeCallMargin = 12
+
 type obstacleSpriteInfo struct {
 	frames          []*ebiten.Image
 	width           float64
@@ -38,26 +46,39 @@ type obstacleSpriteInfo struct {
 }
 
 type obstacleItem struct {
+	behaviour       behaviour
 	obstacleType    obstacleType
 	xPosition       float64
 	speed           float64
 	frameIndex      int
 	frameCount      int
+	ticks           int
 	height          float64
 	width           float64
 	yPosition       float64
+	baseY           float64
+	closest         float64
 	passed          bool
+	hit             bool // The obstacle ran into the player, it can't be cleared anymore
 	isPowerUpObject bool
 }
 
 type Obstacle struct {
 	rng            *rand.Rand
 	player         *character.Player
+	events         *event.Bus
+	coins          *pickup.Coins
 	obstacleImages map[obstacleType]obstacleSpriteInfo
 	obstacles      []obstacleItem
+	projectiles    []projectileItem
+	hazards        []hazardItem
+	layouts        []LevelLayout
+	layout         LevelLayout
 	groundY        float64
 	minObstacleGap float64
 	maxObstacleGap float64
+	minHazardGap   float64
+	coinChance     float64
 	screenWidth    float64
 	obstacleSpeed  float64
 	frameDelay     int
@@ -66,15 +87,21 @@ type Obstacle struct {
 	debug          bool
 }
 
-func NewObstacle(screenWidth, groundY float64, player *character.Player, rng *rand.Rand, maxObstacles int, debug bool) (*Obstacle, error) {
+func NewObstacle(screenWidth, groundY float64, player *character.Player, coins *pickup.Coins, events *event.Bus, rng *rand.Rand, layouts []LevelLayout, maxObstacles int, debug bool) (*Obstacle, error) {
 	obstacle := &Obstacle{
+		layouts:        layouts,
+		layout:         layouts[0],
 		minObstacleGap: 250,
 		maxObstacleGap: 400,
+		minHazardGap:   300,
+		coinChance:     0.3,
 		screenWidth:    screenWidth,
 		rng:            rng,
 		obstacleSpeed:  5,
 		groundY:        groundY,
 		player:         player,
+		coins:          coins,
+		events:         events,
 		frameDelay:     5,
 		scaleFactor:    1.5,
 		maxObstacles:   maxObstacles,
@@ -90,10 +117,15 @@ func NewObstacle(screenWidth, groundY float64, player *character.Player, rng *ra
 
 // loadFrames splits a sprite sheet into individual frames
 func (o *Obstacle) loadFrames(img *ebiten.Image, frameWidth, frameHeight, frameCount int) []*ebiten.Image {
+	return loadFrames(img, frameWidth, frameHeight, frameCount, o.debug)
+}
+
+// loadFrames splits a sprite sheet into individual frames
+func loadFrames(img *ebiten.Image, frameWidth, frameHeight, frameCount int, debug bool) []*ebiten.Image {
 	frames := make([]*ebiten.Image, frameCount)
 	for i := 0; i < frameCount; i++ {
 		frame, ok := img.SubImage(image.Rect(i*frameWidth, 0, (i+1)*frameWidth, frameHeight)).(*ebiten.Image)
-		if ok && o.debug {
+		if ok && debug {
 			fmt.Println("failed to load sub image for obstacle")
 		}
 		frames[i] = frame
@@ -198,41 +230,132 @@ func (o *Obstacle) randomObstacleType(rng *rand.Rand) obstacleType {
 	return types[rng.Intn(len(types))]
 }
 
-// Prepare creates the obstacles
+// Prepare clears the track and lays out the obstacles of a level
 func (o *Obstacle) Prepare() {
-	o.obstacles = []obstacleItem{} // Clear any existing obstacles
+	o.Clear()
+	o.layOut(o.screenWidth + 300)
+}
+
+// Extend lays out the obstacles of another level behind the last one at the current speed, the track is not cleared,
+// like when an endless run moves on
+func (o *Obstacle) Extend() {
+	lastX := o.screenWidth
+	if len(o.obstacles) > 0 {
+		lastX = max(lastX, o.obstacles[len(o.obstacles)-1].xPosition)
+	}
+	// The pits still on the track keep up with the ground, which scrolls at the new speed right away
+	for i := range o.hazards {
+		o.hazards[i].speed = o.obstacleSpeed
+	}
+	o.layOut(lastX)
+}
 
-	var lastX = o.screenWidth + 300
+// layOut appends the obstacles of a level from lastX on, with the pits and the coins in the gaps between them
+func (o *Obstacle) layOut(lastX float64) {
+	previousWidth := 0.0
 	for i := 0; i < o.maxObstacles; i++ {
 		obstacleType := o.randomObstacleType(o.rng)
 
-		// Get the dimensions from the obstacleImages map
-		spriteInfo := o.obstacleImages[obstacleType]
-		obstacleWidth := spriteInfo.width * o.scaleFactor
-		obstacleHeight := spriteInfo.height * o.scaleFactor
-
-		// Calculate the Y position based on obstacle type (e.g., flying or ground-level)
-		var yPosition float64
-		if obstacleType == obstacleTypeVulture {
-			yPosition = o.groundY - 100 - obstacleHeight // Flying obstacle above the ground
-		} else {
-			yPosition = o.groundY - obstacleHeight // Ground-level obstacles
-		}
-
-		// Create obstacle with random gap
+		// Create obstacle with random gap, the first gap is left free for the player to get ready
 		gap := o.rng.Float64()*(o.maxObstacleGap-o.minObstacleGap) + o.minObstacleGap
+		if i > 0 {
+			forced := slices.Contains(o.layout.Pits, i)
+			if forced {
+				// Pits of the layout are always dug, their gap is widened to fit the pit and the run-up on both sides
+				gap = max(gap, o.forcedPitGap(previousWidth))
+			}
+			hazardCount := len(o.hazards)
+			o.placeHazard(lastX+previousWidth, lastX+gap, forced)
+			if len(o.hazards) == hazardCount {
+				// Coins are only placed in gaps that are free of hazards
+				o.placeCoinLine(lastX+previousWidth, lastX+gap)
+			}
+		}
 		lastX += gap
 
-		newObstacle := obstacleItem{
-			xPosition:    lastX,
-			speed:        o.obstacleSpeed,
-			obstacleType: obstacleType,
-			width:        obstacleWidth,
-			height:       obstacleHeight,
-			yPosition:    yPosition,
-		}
+		newObstacle := o.newObstacleItem(obstacleType, lastX)
+		o.placeCoinArc(obstacleType, lastX, newObstacle.width)
 		o.obstacles = append(o.obstacles, newObstacle)
+		previousWidth = newObstacle.width
+	}
+}
+
+// Clear removes the obstacles, the projectiles still in flight, the hazards and the coins from the track
+func (o *Obstacle) Clear() {
+	o.obstacles = []obstacleItem{}
+	o.projectiles = nil
+	o.hazards = nil
+	o.coins.Clear()
+}
+
+// newObstacleItem returns an obstacle of the given type at x, flying obstacles are placed above the ground
+func (o *Obstacle) newObstacleItem(t obstacleType, x float64) obstacleItem {
+	// Get the dimensions from the obstacleImages map
+	spriteInfo := o.obstacleImages[t]
+	obstacleWidth := spriteInfo.width * o.scaleFactor
+	obstacleHeight := spriteInfo.height * o.scaleFactor
+
+	// Calculate the Y position based on obstacle type (e.g., flying or ground-level)
+	var yPosition float64
+	if t == obstacleTypeVulture {
+		yPosition = o.groundY - 100 - obstacleHeight // Flying obstacle above the ground
+	} else {
+		yPosition = o.groundY - obstacleHeight // Ground-level obstacles
+	}
+
+	return obstacleItem{
+		xPosition:    x,
+		speed:        o.obstacleSpeed,
+		obstacleType: t,
+		width:        obstacleWidth,
+		height:       obstacleHeight,
+		yPosition:    yPosition,
+		baseY:        yPosition,
+		closest:      math.MaxFloat64,
+		behaviour:    newBehaviour(t, o.rng, o.screenWidth),
+	}
+}
+
+// Spawn clears the track and sends a single obstacle of the given type in from the right, like in the tutorial
+func (o *Obstacle) Spawn(name string) error {
+	t := obstacleType(name)
+	if _, ok := o.obstacleImages[t]; !ok {
+		return fmt.Errorf("unknown obstacle type %q", name)
+	}
+	o.Clear()
+	o.obstacles = append(o.obstacles, o.newObstacleItem(t, o.screenWidth+50))
+	return nil
+}
+
+// Empty returns true once every obstacle and projectile left the screen
+func (o *Obstacle) Empty() bool {
+	return len(o.obstacles) == 0 && len(o.projectiles) == 0
+}
+
+// placeCoinLine places a line of coins in the middle of a gap between two obstacles
+func (o *Obstacle) placeCoinLine(gapStart, gapEnd float64) {
+	if o.rng.Float64() > o.coinChance {
+		return
+	}
+	// Leave enough room on both sides to jump over the obstacles
+	margin := 100.0
+	if gapEnd-gapStart <= 2*margin {
+		return
 	}
+	o.coins.PlaceLine(gapStart+margin, gapEnd-margin, o.obstacleSpeed)
+}
+
+// placeCoinArc places coins over an obstacle, following the path of a jump
+func (o *Obstacle) placeCoinArc(t obstacleType, x, width float64) {
+	if o.rng.Float64() > o.coinChance {
+		return
+	}
+	if t == obstacleTypeVulture {
+		// Flying obstacles are passed below, so the coins are placed at running height
+		o.coins.PlaceLine(x, x+width, o.obstacleSpeed)
+		return
+	}
+	o.coins.PlaceArc(x+width/2, 90, 100, o.obstacleSpeed)
 }
 
 // IncreaseSpeed increases the speed of the obstacles as the player progresses to new levels
@@ -240,8 +363,24 @@ func (o *Obstacle) IncreaseSpeed() {
 	o.obstacleSpeed += 1.0
 }
 
+// SetSpeed sets the speed of the obstacles sent from now on, like the slow obstacles of the tutorial
+func (o *Obstacle) SetSpeed(speed float64) {
+	o.obstacleSpeed = speed
+}
+
+// Speed returns the current speed of the obstacles, which is the speed the world scrolls at
+func (o *Obstacle) Speed() float64 {
+	return o.obstacleSpeed
+}
+
+// SetLevel picks the layout of the level, it is used from the next Prepare on
+func (o *Obstacle) SetLevel(level int) {
+	o.layout = o.layouts[min(level, len(o.layouts))-1]
+}
+
 func (o *Obstacle) Reset() {
 	o.obstacleSpeed = 5
+	o.SetLevel(1)
 	o.Prepare()
 }
 
@@ -302,22 +441,61 @@ func (o *Obstacle) collisionDetected(obs *obstacleItem) bool {
 	return xOverlap && yOverlap
 }
 
-// cleared returns true when an obstacle was completely passed by player
-func (o *Obstacle) cleared() bool {
+// trackMargin remembers how close the player got to an obstacle while passing over or under it
+func (o *Obstacle) trackMargin(obs *obstacleItem) {
+	spriteInfo := o.obstacleImages[obs.obstacleType]
+
+	// Player's collision boundaries
+	playerLeft := 40 + o.player.CollisionLeft()
+	playerRight := playerLeft + o.player.CollisionWidth()
+	playerTop := o.player.YPosition() + o.player.CollisionTop()
+	playerBottom := playerTop + o.player.CollisionHeight()
+
+	// Obstacle's collision boundaries
+	obstacleLeft := obs.xPosition + spriteInfo.collisionLeft
+	obstacleRight := obstacleLeft + spriteInfo.collisionWidth
+	obstacleTop := obs.yPosition + spriteInfo.collisionTop
+	obstacleBottom := obstacleTop + spriteInfo.collisionHeight
+
+	if playerRight <= obstacleLeft || playerLeft >= obstacleRight {
+		return // Not passing the obstacle yet
+	}
+	// The player is either above a ground obstacle or below a flying one
+	margin := max(obstacleTop-playerBottom, playerTop-obstacleBottom)
+	obs.closest = min(obs.closest, margin)
+}
+
+// cleared publishes an event when an obstacle was completely passed by player
+func (o *Obstacle) cleared() {
 	for i := range o.obstacles {
 		// Check if the obstacle has completely passed the player (xPosition + width is less than player's X)
-		if o.obstacles[i].xPosition+o.obstacles[i].width < 40 && !o.obstacles[i].passed {
+		if o.obstacles[i].xPosition+o.obstacles[i].width < 40 && !o.obstacles[i].passed && !o.obstacles[i].hit {
 			o.obstacles[i].passed = true // Mark the obstacle as passed
-			return true
+			event.Publish(o.events, event.ObstacleCleared{
+				Type:      string(o.obstacles[i].obstacleType),
+				Margin:    o.obstacles[i].closest,
+				CloseCall: o.obstacles[i].closest >= 0 && o.obstacles[i].closest < closeCallMargin,
+			})
+			return
 		}
 	}
-	return false
 }
 
-// Update handles the movement of obstacles and checks for collisions
-func (o *Obstacle) Update() (collision, isPowerUpObject, cleared bool) {
+// Update handles the movement of obstacles and publishes collisions and cleared obstacles
+func (o *Obstacle) Update() {
 	for i := range o.obstacles {
-		o.obstacles[i].xPosition -= o.obstacles[i].speed // Move the obstacle to the left
+		// Move the obstacle according to its behaviour
+		o.obstacles[i].behaviour.update(&o.obstacles[i], o.player.XPosition())
+		o.obstacles[i].ticks++
+		o.trackMargin(&o.obstacles[i])
+
+		// Let obstacles that can shoot fire their projectiles
+		if s, ok := o.obstacles[i].behaviour.(shooter); ok {
+			if projectile, fired := s.fire(&o.obstacles[i], o.groundY); fired {
+				projectile.shooter = o.obstacles[i].obstacleType
+				o.projectiles = append(o.projectiles, projectile)
+			}
+		}
 
 		// Update frame for animation based on frame delay
 		o.obstacles[i].frameCount++
@@ -330,23 +508,49 @@ func (o *Obstacle) Update() (collision, isPowerUpObject, cleared bool) {
 		}
 	}
 	o.obstacles = o.filterObstacles() // Remove obstacles that have moved off-screen
+	o.projectiles = moveProjectiles(o.projectiles, o.groundY)
+	o.updateHazards()
+
+	if collision, found := o.collision(); found {
+		event.Publish(o.events, collision)
+		return
+	}
+	o.cleared()
+}
+
+// collision returns the first thing the player ran into and marks an obstacle as hit, the player can't collide while immune
+func (o *Obstacle) collision() (event.Collision, bool) {
+	if o.player.IsImmune() {
+		return event.Collision{}, false
+	}
 
 	// Check for collisions with each obstacle
-	for _, obs := range o.obstacles {
-		if o.collisionDetected(&obs) {
-			// a collision is detected
-			return true, obs.isPowerUpObject, false
+	for i := range o.obstacles {
+		obs := &o.obstacles[i]
+		if o.collisionDetected(obs) {
+			// a collision is detected, an obstacle the player survived running into doesn't count as cleared
+			obs.hit = true
+			return event.Collision{Source: event.SourceObstacle, Type: string(obs.obstacleType), PowerUp: obs.isPowerUpObject}, true
+		}
+	}
+
+	// Projectiles hurt the player the same way obstacles do, falling into a pit is handled by the player
+	for _, p := range o.projectiles {
+		if projectileCollision(o.player, &p) {
+			return event.Collision{Source: event.SourceProjectile, Type: string(p.projectileType), Shooter: string(p.shooter)}, true
 		}
 	}
-	return false, false, o.cleared()
+	return event.Collision{}, false
 }
 
-// Draw renders the obstacles on the screen
-func (o *Obstacle) Draw(screen *ebiten.Image) {
+// Draw renders the obstacles on the screen through the view of the camera
+func (o *Obstacle) Draw(screen *ebiten.Image, view ebiten.GeoM) {
+	drawProjectiles(screen, o.projectiles, view)
 	for _, obs := range o.obstacles {
 		op := &ebiten.DrawImageOptions{}
 		op.GeoM.Scale(o.scaleFactor, o.scaleFactor)
 		op.GeoM.Translate(obs.xPosition, obs.yPosition)
+		op.GeoM.Concat(view)
 
 		// Draw the current frame for the obstacle using the sprite info
 		currentFrame := o.obstacleImages[obs.obstacleType].frames[obs.frameIndex]
@@ -377,13 +581,14 @@ func (o *Obstacle) Draw(screen *ebiten.Image) {
 				collisionHeight = spriteInfo.height // Use the full sprite height if not specified
 			}
 
+			x, y, width, height := camera.Rect(view, obs.xPosition+collisionLeft, obs.yPosition+collisionTop, collisionWidth, collisionHeight)
 			vector.DrawFilledRect(
 				screen,
-				float32(obs.xPosition+collisionLeft), // X position
-				float32(obs.yPosition+collisionTop),  // YPosition position
-				float32(collisionWidth),              // Width of the obstacle
-				float32(collisionHeight),             // Height of the obstacle
-				color.RGBA{R: 255, A: 128},           // Color of the rectangle (Red with 50% transparency)
+				x,                          // X position
+				y,                          // YPosition position
+				width,                      // Width of the obstacle
+				height,                     // Height of the obstacle
+				color.RGBA{R: 255, A: 128}, // Color of the rectangle (Red with 50% transparency)
 				false,
 			)
 		}
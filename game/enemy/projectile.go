@@ -0,0 +1,109 @@
+package enemy
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tejashwikalptaru/go.run/game/camera"
+	"github.com/tejashwikalptaru/go.run/game/character"
+)
+
+type projectileType string
+
+const (
+	projectileTypeVenom projectileType = "venom"
+	projectileTypeRock  projectileType = "rock"
+)
+
+type projectileItem struct {
+	projectileType projectileType
+	shooter        obstacleType // Obstacle that fired the projectile, empty for the boss
+	xPosition      float64
+	yPosition      float64
+	velocityX      float64
+	velocityY      float64
+	gravity        float64
+	radius         float64
+	grounded       bool
+}
+
+// shooter is implemented by behaviours that fire projectiles
+type shooter interface {
+	fire(obs *obstacleItem, groundY float64) (projectileItem, bool)
+}
+
+// newVenom creates a venom blob spat along the ground
+func newVenom(x, groundY, speed float64) projectileItem {
+	radius := 6.0
+	return projectileItem{
+		projectileType: projectileTypeVenom,
+		xPosition:      x,
+		yPosition:      groundY - radius - 4,
+		velocityX:      speed,
+		radius:         radius,
+		grounded:       true,
+	}
+}
+
+// newRock creates a falling rock, it rolls along the ground after landing
+func newRock(x, y, speed float64) projectileItem {
+	return projectileItem{
+		projectileType: projectileTypeRock,
+		xPosition:      x,
+		yPosition:      y,
+		velocityX:      speed,
+		gravity:        0.3,
+		radius:         9,
+	}
+}
+
+// moveProjectiles moves the projectiles and removes the ones that left the screen
+func moveProjectiles(projectiles []projectileItem, groundY float64) []projectileItem {
+	var filtered []projectileItem
+	for _, p := range projectiles {
+		p.xPosition -= p.velocityX
+		if !p.grounded {
+			p.yPosition += p.velocityY
+			p.velocityY += p.gravity
+
+			// Stop falling once the projectile touches the ground
+			if p.yPosition+p.radius >= groundY {
+				p.yPosition = groundY - p.radius
+				p.velocityY = 0
+				p.grounded = true
+			}
+		}
+		if p.xPosition > -p.radius {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// projectileCollision checks for a collision between the player and a projectile
+func projectileCollision(player *character.Player, p *projectileItem) bool {
+	// Player's collision boundaries
+	playerLeft := 40 + player.CollisionLeft()
+	playerRight := playerLeft + player.CollisionWidth()
+	playerTop := player.YPosition() + player.CollisionTop()
+	playerBottom := playerTop + player.CollisionHeight()
+
+	// Projectiles collide as a square around their center
+	xOverlap := playerRight > p.xPosition-p.radius && playerLeft < p.xPosition+p.radius
+	yOverlap := playerBottom > p.yPosition-p.radius && playerTop < p.yPosition+p.radius
+
+	return xOverlap && yOverlap
+}
+
+// drawProjectiles renders the projectiles on the screen through the view
+func drawProjectiles(screen *ebiten.Image, projectiles []projectileItem, view ebiten.GeoM) {
+	for _, p := range projectiles {
+		clr := color.RGBA{R: 110, G: 110, B: 110, A: 255} // Grey rock
+		if p.projectileType == projectileTypeVenom {
+			clr = color.RGBA{R: 90, G: 220, B: 40, A: 255} // Green venom
+		}
+		x, y, radius := camera.Circle(view, p.xPosition, p.yPosition, p.radius)
+		vector.DrawFilledCircle(screen, x, y, radius, clr, true)
+	}
+}
@@ -0,0 +1,39 @@
+package event
+
+import "reflect"
+
+// Bus delivers game events to the subsystems subscribed to them, events are delivered synchronously in the order
+// the handlers were subscribed
+type Bus struct {
+	handlers    map[reflect.Type][]func(any)
+	allHandlers []func(any)
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		handlers: map[reflect.Type][]func(any){},
+	}
+}
+
+// Subscribe registers a handler for every published event of type T
+func Subscribe[T any](b *Bus, handler func(T)) {
+	eventType := reflect.TypeFor[T]()
+	b.handlers[eventType] = append(b.handlers[eventType], func(e any) {
+		handler(e.(T))
+	})
+}
+
+// SubscribeAll registers a handler for every published event, like a telemetry logger
+func (b *Bus) SubscribeAll(handler func(any)) {
+	b.allHandlers = append(b.allHandlers, handler)
+}
+
+// Publish delivers the event to its handlers
+func Publish[T any](b *Bus, e T) {
+	for _, handler := range b.handlers[reflect.TypeFor[T]()] {
+		handler(e)
+	}
+	for _, handler := range b.allHandlers {
+		handler(e)
+	}
+}
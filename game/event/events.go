@@ -0,0 +1,69 @@
+package event
+
+// Jumped is published when the player leaves the ground
+type Jumped struct{}
+
+// Landed is published when the player touches the ground after a jump
+type Landed struct{}
+
+// ObstacleCleared is published when the player got past an obstacle
+type ObstacleCleared struct {
+	Type      string
+	Margin    float64 // Closest gap in pixels between the player and the obstacle
+	CloseCall bool
+}
+
+// Collision sources
+const (
+	SourceObstacle   = "obstacle"
+	SourceProjectile = "projectile"
+	SourceHazard     = "hazard"
+	SourceBoss       = "boss"
+)
+
+// Collision is published when the player runs into something
+type Collision struct {
+	Source  string
+	Type    string // Obstacle, projectile or hazard type
+	Shooter string // Obstacle type that fired the projectile, empty for anything but projectiles
+	PowerUp bool   // The player ran into a power-up, not into an enemy
+}
+
+// PowerUpUsed is published when a power-up protected the player
+type PowerUpUsed struct {
+	Name string
+}
+
+// CoinsCollected is published when the player picked up coins
+type CoinsCollected struct {
+	Count int
+}
+
+// ScoreChanged is published when points were scored
+type ScoreChanged struct {
+	Score      int
+	Points     int
+	Combo      int
+	Multiplier int
+	CloseCall  bool
+}
+
+// LevelStarted is published when a new level begins
+type LevelStarted struct {
+	Level int
+}
+
+// LevelCleared is published when the player left a level
+type LevelCleared struct {
+	Level int
+	Boss  bool // A boss was beaten at the end of the level
+}
+
+// GameOver is published when the run ends
+type GameOver struct {
+	KilledBy string // Type of the obstacle, pit or boss that ended the run, projectiles are charged to the obstacle that fired them
+	Source   string // Source of the collision that ended the run, like obstacle or hazard
+	Score    int
+	Level    int
+	Earned   int
+}
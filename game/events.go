@@ -0,0 +1,78 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/tejashwikalptaru/go.run/game/event"
+)
+
+// subscribe wires scoring, lives and the HUD to the game events
+func (g *Game) subscribe() {
+	event.Subscribe(g.Events, g.onObstacleCleared)
+	event.Subscribe(g.Events, g.onCollision)
+	event.Subscribe(g.Events, g.onJumped)
+	event.Subscribe(g.Events, g.onLanded)
+	event.Subscribe(g.Events, g.onScoreChanged)
+	event.Subscribe(g.Events, g.onLevelCleared)
+
+	if g.debug {
+		// Log every event as a simple telemetry trace
+		g.Events.SubscribeAll(func(e any) {
+			fmt.Printf("event %T %+v\n", e, e)
+		})
+	}
+}
+
+// onObstacleCleared increases score and jumps count, a close call is worth a bonus
+func (g *Game) onObstacleCleared(e event.ObstacleCleared) {
+	points := g.Level.IncreaseScore(e.CloseCall)
+	event.Publish(g.Events, event.ScoreChanged{
+		Score:      g.Level.Score(),
+		Points:     points,
+		Combo:      g.Level.Combo(),
+		Multiplier: g.Level.Multiplier(),
+		CloseCall:  e.CloseCall,
+	})
+}
+
+// onCollision hurts the player, running into a power-up doesn't
+func (g *Game) onCollision(e event.Collision) {
+	if !e.PowerUp {
+		g.hit(e)
+	}
+}
+
+// onJumped lets the combo know that a jump started
+func (g *Game) onJumped(event.Jumped) {
+	g.Level.Jumped()
+}
+
+// onLanded lets the combo know that a jump is over
+func (g *Game) onLanded(event.Landed) {
+	g.Level.Landed()
+}
+
+// onScoreChanged shows the points scored next to the runner
+func (g *Game) onScoreChanged(e event.ScoreChanged) {
+	g.showPoints(e.Points, e.CloseCall)
+}
+
+// onLevelCleared punches the camera in on the runner
+func (g *Game) onLevelCleared(event.LevelCleared) {
+	g.Camera.Punch(0.08, 40, g.Player.XPosition()+g.Player.Width()/2, g.Player.YPosition()+g.Player.Height()/2)
+}
+
+// showPoints shows the points scored for a cleared obstacle next to the runner
+func (g *Game) showPoints(points int, closeCall bool) {
+	msg := fmt.Sprintf("+%d", points)
+	if multiplier := g.Level.Multiplier(); multiplier > 1 {
+		msg += fmt.Sprintf(" x%d", multiplier)
+	}
+	clr := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	if closeCall {
+		msg = g.Messages.Text("popup.closeCall", msg)
+		clr = color.RGBA{R: 255, G: 210, B: 40, A: 255}
+	}
+	g.Popups.Add(g.Player.XPosition()+g.Player.Width(), g.Player.YPosition()-10, msg, clr)
+}
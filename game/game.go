@@ -1,51 +1,130 @@
 package game
 
 import (
+	"errors"
+	"fmt"
 	"math/rand"
 	"time"
 
-	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/tejashwikalptaru/go.run/resources/data"
 	"github.com/tejashwikalptaru/go.run/resources/fonts"
+	"github.com/tejashwikalptaru/go.run/resources/locales"
+	"github.com/tejashwikalptaru/go.run/resources/shaders"
 
+	"github.com/tejashwikalptaru/go.run/game/achievement"
 	"github.com/tejashwikalptaru/go.run/game/background"
+	"github.com/tejashwikalptaru/go.run/game/camera"
 	"github.com/tejashwikalptaru/go.run/game/character"
+	"github.com/tejashwikalptaru/go.run/game/display"
 	"github.com/tejashwikalptaru/go.run/game/enemy"
+	"github.com/tejashwikalptaru/go.run/game/event"
+	"github.com/tejashwikalptaru/go.run/game/hud"
+	"github.com/tejashwikalptaru/go.run/game/locale"
 	"github.com/tejashwikalptaru/go.run/game/music"
+	"github.com/tejashwikalptaru/go.run/game/pickup"
+	"github.com/tejashwikalptaru/go.run/game/postfx"
+	"github.com/tejashwikalptaru/go.run/game/profile"
+	"github.com/tejashwikalptaru/go.run/game/shop"
 	"github.com/tejashwikalptaru/go.run/game/stage"
+	"github.com/tejashwikalptaru/go.run/game/stats"
+	"github.com/tejashwikalptaru/go.run/game/title"
+	"github.com/tejashwikalptaru/go.run/game/transition"
+	"github.com/tejashwikalptaru/go.run/game/tutorial"
+	"github.com/tejashwikalptaru/go.run/game/ui"
 )
 
 const (
 	ScreenWidth    = 800
 	ScreenHeight   = 400
 	LevelThreshold = 50
+	BossEvery      = 3
+	ScorePerCoin   = 20 // Every 20 points scored in a run pay out one coin
 )
 
 // Game struct holds game state variables
 type Game struct {
-	RNG            *rand.Rand
-	Scene          *background.Scene
-	Cloud          *background.Cloud
-	Obstacle       *enemy.Obstacle
-	Player         *character.Player
-	Level          *stage.Level
-	MusicManager   *music.Manager
-	TextFaceSource *text.GoTextFaceSource
-	GameOver       bool
-	debug          bool
+	RNG           *rand.Rand
+	Events        *event.Bus
+	Scene         *background.Scene
+	DayCycle      *background.DayCycle
+	Camera        *camera.Camera
+	Obstacle      *enemy.Obstacle
+	Boss          *enemy.Boss
+	Player        *character.Player
+	Coins         *pickup.Coins
+	Profile       *profile.Profile
+	Shop          *shop.Shop
+	Title         *title.Title
+	Display       *display.Display
+	PostFX        *postfx.Pipeline
+	Achievements  *achievement.Achievements
+	Level         *stage.Level
+	Popups        *stage.Popups
+	HUD           *hud.HUD
+	Stats         *stats.Tracker
+	Summary       *stats.Summary
+	Transition    *transition.Transition
+	Tutorial      *tutorial.Tutorial
+	MusicManager  *music.Manager
+	Text          *ui.Text
+	Messages      *locale.Messages
+	Lives         int
+	Earned        int
+	world         *ebiten.Image  // Offscreen image the world is drawn to before it is lit
+	frame         *ebiten.Image  // The lit world of the current scene
+	previousFrame *ebiten.Image  // The lit world of the previous scene while a transition runs
+	finished      *ebiten.Image  // The whole frame before the post-processing effects
+	darkness      *ebiten.Shader // Limits the view around the runner at night
+	GameOver      bool
+	endless       bool // The run keeps going without countdowns or bosses
+	demo          bool // A demo run plays behind the title screen
+	debug         bool
 }
 
-// Layout defines the screen dimensions
+// Layout defines the screen dimensions, it is only used when LayoutF is not
 func (g *Game) Layout(_, _ int) (screenWidth, screenHeight int) {
 	return ScreenWidth, ScreenHeight
 }
 
+// LayoutF sizes the screen to the window, the game is scaled to it by the display
+func (g *Game) LayoutF(outsideWidth, outsideHeight float64) (screenWidth, screenHeight float64) {
+	return g.Display.Layout(outsideWidth, outsideHeight)
+}
+
 // NewGame initializes a new game instance
 func NewGame(debug bool) (*Game, error) {
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	events := event.NewBus()
+
+	// ManaSpace is the look of the game, M+ draws the letters it is missing, like Cyrillic and Japanese
+	fontFamily, fontFamilyErr := fonts.LoadFamily(fonts.ManaSpace, fonts.MPlus)
+	if fontFamilyErr != nil {
+		return nil, fontFamilyErr
+	}
+	uiText := ui.NewText(fontFamily)
 
-	textFaceSource, textFaceSourceErr := fonts.LoadFont(fonts.ManaSpace)
-	if textFaceSourceErr != nil {
-		return nil, textFaceSourceErr
+	// load the translations of the on-screen texts
+	messages, messagesErr := locale.Load(locales.Files)
+	if messagesErr != nil {
+		return nil, messagesErr
+	}
+
+	// load the profile that was played last
+	activeProfile, activeErr := profile.Active()
+	if activeErr != nil {
+		return nil, activeErr
+	}
+	playerProfile, tampered, profileErr := loadProfile(activeProfile)
+	if profileErr != nil {
+		return nil, profileErr
+	}
+	messages.SetLanguage(playerProfile.Settings.Language)
+
+	// load the achievement definitions
+	achievements, achievementsErr := achievement.LoadDefinitions(data.Achievements)
+	if achievementsErr != nil {
+		return nil, achievementsErr
 	}
 
 	// initialise music manager
@@ -54,46 +133,127 @@ func NewGame(debug bool) (*Game, error) {
 		return nil, musicErr
 	}
 
+	// load the scene definitions
+	scenes, scenesErr := background.LoadScenes(data.Scenes)
+	if scenesErr != nil {
+		return nil, scenesErr
+	}
+
+	// initialise the time of day and the darkness shader
+	dayCycle := background.NewDayCycle(ScreenWidth, ScreenHeight, rng)
+	darkness, darknessErr := ebiten.NewShader(shaders.Darkness)
+	if darknessErr != nil {
+		return nil, darknessErr
+	}
+
 	// initialise background scene
-	scene, sceneErr := background.NewScene(ScreenWidth, ScreenHeight)
+	scene, sceneErr := background.NewScene(ScreenWidth, ScreenHeight, scenes, dayCycle, rng)
 	if sceneErr != nil {
 		return nil, sceneErr
 	}
 
-	// initialise cloud
-	cloud, cloudErr := background.NewCloud(ScreenWidth, ScreenHeight, rng)
-	if cloudErr != nil {
-		return nil, cloudErr
+	// initialise the transitions between the levels
+	transitions, transitionsErr := transition.LoadDefinitions(data.Transitions)
+	if transitionsErr != nil {
+		return nil, transitionsErr
+	}
+	sceneTransition, transitionErr := transition.NewTransition(ScreenWidth, ScreenHeight, transitions)
+	if transitionErr != nil {
+		return nil, transitionErr
+	}
+
+	// load the steps of the tutorial
+	tutorialSteps, tutorialErr := tutorial.LoadSteps(data.Tutorial)
+	if tutorialErr != nil {
+		return nil, tutorialErr
+	}
+
+	// initialise the post-processing effects
+	pipeline, pipelineErr := postfx.NewPipeline(ScreenWidth, ScreenHeight)
+	if pipelineErr != nil {
+		return nil, pipelineErr
 	}
 
 	// initialise character
-	player, playerErr := character.NewPlayer(ScreenWidth, scene.GroundY(), musicManager, debug)
+	player, playerErr := character.NewPlayer(ScreenWidth, scene.GroundY(), events, debug)
 	if playerErr != nil {
 		return nil, playerErr
 	}
 
+	// initialise coins
+	coins := pickup.NewCoins(scene.GroundY(), player, events)
+
+	// load the level layouts
+	layouts, layoutsErr := enemy.LoadLayouts(data.Levels)
+	if layoutsErr != nil {
+		return nil, layoutsErr
+	}
+
 	// initialise obstacle
-	obstacle, obstacleErr := enemy.NewObstacle(ScreenWidth, scene.GroundY(), player, rng, LevelThreshold, debug)
+	obstacle, obstacleErr := enemy.NewObstacle(ScreenWidth, scene.GroundY(), player, coins, events, rng, layouts, LevelThreshold, debug)
 	if obstacleErr != nil {
 		return nil, obstacleErr
 	}
 
+	// initialise boss
+	boss, bossErr := enemy.NewBoss(ScreenWidth, scene.GroundY(), player, events, rng, debug)
+	if bossErr != nil {
+		return nil, bossErr
+	}
+
 	game := &Game{
-		TextFaceSource: textFaceSource,
-		RNG:            rng,
-		Scene:          scene,
-		Cloud:          cloud,
-		Obstacle:       obstacle,
-		Player:         player,
-		GameOver:       false,
-		debug:          debug,
-		MusicManager:   musicManager,
+		Text:          uiText,
+		Messages:      messages,
+		RNG:           rng,
+		Events:        events,
+		Scene:         scene,
+		DayCycle:      dayCycle,
+		Camera:        camera.NewCamera(ScreenWidth, ScreenHeight, rng),
+		Transition:    sceneTransition,
+		Tutorial:      tutorial.NewTutorial(ScreenWidth, ScreenHeight, uiText, messages, tutorialSteps),
+		world:         ebiten.NewImage(ScreenWidth, ScreenHeight),
+		frame:         ebiten.NewImage(ScreenWidth, ScreenHeight),
+		previousFrame: ebiten.NewImage(ScreenWidth, ScreenHeight),
+		finished:      ebiten.NewImage(ScreenWidth, ScreenHeight),
+		PostFX:        pipeline,
+		darkness:      darkness,
+		Obstacle:      obstacle,
+		Boss:          boss,
+		Player:        player,
+		Coins:         coins,
+		Profile:       playerProfile,
+		Display:       display.NewDisplay(ScreenWidth, ScreenHeight, playerProfile),
+		Shop:          shop.NewShop(ScreenWidth, ScreenHeight, uiText, messages, playerProfile, player),
+		Popups:        stage.NewPopups(uiText),
+		HUD:           hud.NewHUD(uiText, messages),
+		Stats:         stats.NewTracker(),
+		Summary:       stats.NewSummary(ScreenWidth, ScreenHeight, uiText, messages),
+		Achievements:  achievement.NewAchievements(achievements, playerProfile, uiText, messages, ScreenWidth),
+		GameOver:      false,
+		debug:         debug,
+		MusicManager:  musicManager,
+	}
+
+	game.Title = title.NewTitle(ScreenWidth, ScreenHeight, uiText, messages, playerProfile, game.selectProfile)
+	if tampered {
+		game.Title.SetNotice(messages.Text("profiles.tampered", activeProfile))
 	}
 
 	// initialise stage
-	level := stage.NewLevel(ScreenWidth, ScreenHeight, textFaceSource, LevelThreshold)
+	level := stage.NewLevel(ScreenWidth, ScreenHeight, uiText, messages, LevelThreshold, BossEvery)
 	game.Level = level
 
+	// let the subsystems listen to the game events
+	game.subscribe()
+	musicManager.Listen(events)
+	game.Achievements.Listen(events)
+	game.Stats.Listen(events)
+
+	// apply the bought skin and starting power-ups
+	player.SetSkin(playerProfile.Skin)
+	game.applyUpgrades()
+	event.Publish(events, event.LevelStarted{Level: game.Level.Number()})
+
 	// Start playing the background music
 	game.MusicManager.PlayBackground()
 
@@ -103,9 +263,127 @@ func NewGame(debug bool) (*Game, error) {
 // ResetGame resets the game state
 func (g *Game) ResetGame() error {
 	g.Scene.Reset()
+	g.Transition.Stop()
+	g.Tutorial.Stop()
+	g.Camera.Reset()
+	g.DayCycle.Reset()
+	g.Coins.Reset()
 	g.Obstacle.Reset()
+	g.Boss.Reset()
 	g.Player.Reset()
-	g.Level = stage.NewLevel(ScreenWidth, ScreenHeight, g.TextFaceSource, LevelThreshold)
+	g.Level = stage.NewLevel(ScreenWidth, ScreenHeight, g.Text, g.Messages, LevelThreshold, BossEvery)
+	g.Popups.Reset()
+	g.Achievements.StartRun()
+	g.Stats.Start()
+	g.Summary.Reset()
 	g.GameOver = false
+	g.applyUpgrades()
+	event.Publish(g.Events, event.LevelStarted{Level: g.Level.Number()})
 	return nil
 }
+
+// loadProfile loads the named profile, a tampered profile is set aside and replaced by a new one, tampered is true then
+func loadProfile(name string) (playerProfile *profile.Profile, tampered bool, loadErr error) {
+	playerProfile, loadErr = profile.Load(name)
+	if !errors.Is(loadErr, profile.ErrChecksumMismatch) {
+		return playerProfile, false, loadErr
+	}
+	asidePath, asideErr := profile.SetAside(name)
+	if asideErr != nil {
+		return nil, true, asideErr
+	}
+	fmt.Printf("saved profile %s was modified outside the game, it was moved to %s and a new one started\n", name, asidePath)
+	playerProfile, loadErr = profile.New(name)
+	return playerProfile, true, loadErr
+}
+
+// selectProfile switches every subsystem over to the named profile and remembers it for the next start
+func (g *Game) selectProfile(name string) error {
+	playerProfile, tampered, profileErr := loadProfile(name)
+	if profileErr != nil {
+		return profileErr
+	}
+	if activeErr := profile.SetActive(name); activeErr != nil {
+		return activeErr
+	}
+	g.Profile = playerProfile
+	g.Shop.SetProfile(playerProfile)
+	g.Title.SetProfile(playerProfile)
+	g.Display.SetProfile(playerProfile)
+	g.Achievements.SetProfile(playerProfile)
+	g.Player.SetSkin(playerProfile.Skin)
+	g.applyUpgrades()
+	if tampered {
+		g.Title.SetNotice(g.Messages.Text("profiles.tampered", name))
+	}
+	return nil
+}
+
+// applyUpgrades gives the player the lives and shield bought in the shop
+func (g *Game) applyUpgrades() {
+	g.Lives = 1 + g.Profile.ExtraLives
+	g.Player.SetShield(g.Profile.StartingShield)
+}
+
+// setPractice keeps the statistics and achievements from counting anything, like during the tutorial and the demo run
+func (g *Game) setPractice(practice bool) {
+	g.Achievements.SetPractice(practice)
+	g.Stats.SetPractice(practice)
+}
+
+// hit handles the player running into an obstacle, a projectile or the boss
+func (g *Game) hit(collision event.Collision) {
+	if g.Player.IsImmune() {
+		return
+	}
+	g.Camera.Shake(6, 20)
+	g.Camera.HitStop(6)
+	if g.Tutorial.Active() {
+		// Nothing is lost in the tutorial, the step starts over
+		g.Tutorial.Failed()
+		g.Player.MakeImmune()
+		return
+	}
+	if g.demo {
+		// The demo run never ends, so it never reaches the profile
+		g.Player.MakeImmune()
+		return
+	}
+	g.Level.BreakCombo()
+	if g.Player.UseShield() {
+		event.Publish(g.Events, event.PowerUpUsed{Name: "shield"})
+		return
+	}
+	g.Lives--
+	if g.Lives <= 0 {
+		// A projectile is charged to the obstacle that fired it
+		killedBy := collision.Type
+		if collision.Shooter != "" {
+			killedBy = collision.Shooter
+		}
+		g.endRun(killedBy, collision.Source)
+		return
+	}
+	g.Player.MakeImmune()
+}
+
+// endRun ends the current run and banks the coins collected and earned during it
+func (g *Game) endRun(killedBy, source string) {
+	g.GameOver = true
+	g.Earned = g.Coins.Collected() + g.Level.Score()/ScorePerCoin
+	g.Profile.AddCoins(g.Earned)
+	g.Profile.AddHighScore(g.Level.Score(), g.Level.Number())
+	event.Publish(g.Events, event.GameOver{
+		KilledBy: killedBy,
+		Source:   source,
+		Score:    g.Level.Score(),
+		Level:    g.Level.Number(),
+		Earned:   g.Earned,
+	})
+
+	// The run statistics are complete once the game over was published
+	g.Stats.Run().AddTo(&g.Profile.Lifetime)
+	if saveErr := g.Profile.Save(); saveErr != nil {
+		fmt.Printf("failed to save profile: %v", saveErr)
+	}
+}
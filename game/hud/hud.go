@@ -0,0 +1,216 @@
+package hud
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tejashwikalptaru/go.run/game/locale"
+	"github.com/tejashwikalptaru/go.run/game/ui"
+)
+
+const (
+	margin        = 10 // Space between the panels and the edges of the screen
+	padding       = 8  // Space between the edge of a panel and its content
+	largeTextSize = 20
+	smallTextSize = 12
+	progressWidth = 200
+	heartPixel    = 2 // Size of a pixel of the heart icon
+)
+
+var (
+	panelColor    = color.RGBA{A: 140}
+	textColor     = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	accentColor   = color.RGBA{R: 255, G: 210, B: 40, A: 255}
+	shadowColor   = color.RGBA{A: 200}
+	heartColor    = color.RGBA{R: 220, G: 40, B: 50, A: 255}
+	shieldColor   = color.RGBA{R: 80, G: 180, B: 255, A: 255}
+	immuneColor   = color.RGBA{R: 200, G: 200, B: 200, A: 255}
+	progressColor = color.RGBA{R: 90, G: 200, B: 90, A: 255}
+	bossColor     = color.RGBA{R: 220, G: 60, B: 60, A: 255}
+)
+
+// heart is the pixel art of a life
+var heart = []string{
+	".XX.XX.",
+	"XXXXXXX",
+	"XXXXXXX",
+	".XXXXX.",
+	"..XXX..",
+	"...X...",
+}
+
+// Anchor is the corner or edge of the screen a panel sticks to
+type Anchor int
+
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTopCenter
+	AnchorTopRight
+	AnchorBottomLeft
+	AnchorBottomRight
+)
+
+// position returns the top left of a panel of the size stuck to the anchor of the screen
+func (a Anchor) position(screen *ebiten.Image, width, height float64) (float64, float64) {
+	bounds := screen.Bounds()
+	left, top := float64(bounds.Min.X)+margin, float64(bounds.Min.Y)+margin
+	right, bottom := float64(bounds.Max.X)-margin-width, float64(bounds.Max.Y)-margin-height
+	switch a {
+	case AnchorTopCenter:
+		return float64(bounds.Min.X) + (float64(bounds.Dx())-width)/2, top
+	case AnchorTopRight:
+		return right, top
+	case AnchorBottomLeft:
+		return left, bottom
+	case AnchorBottomRight:
+		return right, bottom
+	}
+	return left, top
+}
+
+// State is what the HUD shows
+type State struct {
+	Score      int
+	Multiplier int
+	Level      int
+	Progress   float64 // Share of the jumps needed to clear the level
+	Boss       bool    // The level ends with a boss
+	Speed      float64
+	Lives      int
+	Coins      int
+	Shield     bool
+	ImmuneLeft float64 // Share of the immunity after a hit that is left
+}
+
+// HUD shows the score, the level and its progress, the speed, the lives and the active power-ups
+type HUD struct {
+	text     *ui.Text
+	messages *locale.Messages
+}
+
+func NewHUD(uiText *ui.Text, messages *locale.Messages) *HUD {
+	return &HUD{text: uiText, messages: messages}
+}
+
+func (h *HUD) Draw(screen *ebiten.Image, state State) {
+	h.drawScore(screen, state)
+	h.drawLevel(screen, state)
+	h.drawLives(screen, state)
+	h.drawSpeed(screen, state)
+	h.drawPowerUps(screen, state)
+}
+
+// drawScore shows the score and the combo multiplier in the top left
+func (h *HUD) drawScore(screen *ebiten.Image, state State) {
+	x, y := AnchorTopLeft.position(screen, 150, 48)
+	h.drawPanel(screen, x, y, 150, 48)
+	h.drawText(screen, fmt.Sprintf("%d", state.Score), x+padding, y+padding, largeTextSize, textColor)
+	if state.Multiplier > 1 {
+		h.drawText(screen, h.messages.Text("hud.combo", state.Multiplier), x+padding, y+padding+largeTextSize+2, smallTextSize, accentColor)
+	}
+}
+
+// drawLevel shows the level and a bar of the jumps left until it is cleared at the top center
+func (h *HUD) drawLevel(screen *ebiten.Image, state State) {
+	width, height := float64(progressWidth+padding*2), 44.0
+	x, y := AnchorTopCenter.position(screen, width, height)
+	h.drawPanel(screen, x, y, width, height)
+
+	label := h.messages.Text("hud.level", state.Level)
+	barColor := progressColor
+	if state.Boss {
+		label = h.messages.Text("hud.bossLevel", state.Level)
+		barColor = bossColor
+	}
+	h.drawText(screen, label, x+padding, y+padding, smallTextSize, textColor)
+
+	barY := float32(y + padding + smallTextSize + 6)
+	vector.DrawFilledRect(screen, float32(x+padding), barY, progressWidth, 8, color.RGBA{R: 40, G: 40, B: 40, A: 255}, false)
+	vector.DrawFilledRect(screen, float32(x+padding), barY, float32(progressWidth*state.Progress), 8, barColor, false)
+	vector.StrokeRect(screen, float32(x+padding), barY, progressWidth, 8, 1, textColor, false)
+}
+
+// drawLives shows a heart for every life and the coins collected in the top right
+func (h *HUD) drawLives(screen *ebiten.Image, state State) {
+	heartWidth := float64(len(heart[0])*heartPixel + 4)
+	width := max(float64(state.Lives)*heartWidth, 70) + padding*2
+	x, y := AnchorTopRight.position(screen, width, 48)
+	h.drawPanel(screen, x, y, width, 48)
+
+	for i := 0; i < state.Lives; i++ {
+		drawHeart(screen, x+padding+float64(i)*heartWidth, y+padding)
+	}
+	coinY := y + padding + float64(len(heart)*heartPixel) + 6
+	vector.DrawFilledCircle(screen, float32(x+padding+5), float32(coinY+6), 5, accentColor, true)
+	h.drawText(screen, fmt.Sprintf("%d", state.Coins), x+padding+14, coinY, smallTextSize, textColor)
+}
+
+// drawSpeed shows how fast the world moves in the bottom left
+func (h *HUD) drawSpeed(screen *ebiten.Image, state State) {
+	label := h.messages.Text("hud.speed", fmt.Sprintf("%.1f", state.Speed))
+	width := max(h.textWidth(label, smallTextSize)+padding*2, 110)
+	x, y := AnchorBottomLeft.position(screen, width, 28)
+	h.drawPanel(screen, x, y, width, 28)
+	h.drawText(screen, label, x+padding, y+padding, smallTextSize, textColor)
+}
+
+// drawPowerUps shows the shield and the immunity after a hit in the bottom right, the immunity drains away
+func (h *HUD) drawPowerUps(screen *ebiten.Image, state State) {
+	type badge struct {
+		label string
+		clr   color.RGBA
+		left  float64 // Share left of a power-up that runs out, 1 for one that lasts
+	}
+	var badges []badge
+	if state.Shield {
+		badges = append(badges, badge{label: h.messages.Text("hud.shield"), clr: shieldColor, left: 1})
+	}
+	if state.ImmuneLeft > 0 {
+		badges = append(badges, badge{label: h.messages.Text("hud.immune"), clr: immuneColor, left: state.ImmuneLeft})
+	}
+	if len(badges) == 0 {
+		return
+	}
+
+	// The badges are as wide as the longest label, translations can be longer than the English ones
+	const badgeHeight = 28.0
+	badgeWidth := 80.0
+	for _, b := range badges {
+		badgeWidth = max(badgeWidth, h.textWidth(b.label, smallTextSize)+padding*2)
+	}
+	width := float64(len(badges))*(badgeWidth+4) - 4
+	x, y := AnchorBottomRight.position(screen, width, badgeHeight)
+	for i, b := range badges {
+		bx := x + float64(i)*(badgeWidth+4)
+		h.drawPanel(screen, bx, y, badgeWidth, badgeHeight)
+		h.drawText(screen, b.label, bx+padding, y+padding, smallTextSize, b.clr)
+		vector.DrawFilledRect(screen, float32(bx+padding), float32(y+badgeHeight-5), float32((badgeWidth-padding*2)*b.left), 2, b.clr, false)
+	}
+}
+
+func (h *HUD) drawPanel(screen *ebiten.Image, x, y, width, height float64) {
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(height), panelColor, false)
+}
+
+// textWidth returns how wide the text is drawn in the size
+func (h *HUD) textWidth(msg string, size float64) float64 {
+	return h.text.Width(msg, ui.Style{Size: size})
+}
+
+// drawText draws the text with a drop shadow so it reads on any background
+func (h *HUD) drawText(screen *ebiten.Image, msg string, x, y, size float64, clr color.Color) {
+	h.text.Draw(screen, msg, x, y, ui.Style{Size: size, Color: clr, Shadow: shadowColor})
+}
+
+// drawHeart draws the pixel art heart with its top left at x and y
+func drawHeart(screen *ebiten.Image, x, y float64) {
+	for row, line := range heart {
+		for col, pixel := range line {
+			if pixel == 'X' {
+				vector.DrawFilledRect(screen, float32(x)+float32(col*heartPixel), float32(y)+float32(row*heartPixel), heartPixel, heartPixel, heartColor, false)
+			}
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package locale
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is used when no language was picked and for the messages a translation is missing
+const DefaultLanguage = "en"
+
+// Plural forms a message can have, the plural rule of a language picks one by a count
+const (
+	formOne   = "one"
+	formFew   = "few"
+	formMany  = "many"
+	formOther = "other"
+)
+
+// pluralRules pick the plural form for a count, locale files name the rule their language follows
+var pluralRules = map[string]func(n int) string{
+	// English, German and most other European languages
+	"one-other": func(n int) string {
+		if n == 1 {
+			return formOne
+		}
+		return formOther
+	},
+	// Russian, Ukrainian and the other East Slavic languages
+	"one-few-many": func(n int) string {
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return formOne
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return formFew
+		}
+		return formMany
+	},
+	// Japanese, Chinese and other languages without plurals
+	"other": func(int) string {
+		return formOther
+	},
+}
+
+// ruleForms are the plural forms every rule may pick, a plural message needs all of them, the most general form comes last
+var ruleForms = map[string][]string{
+	"one-other":    {formOne, formOther},
+	"one-few-many": {formOne, formFew, formMany},
+	"other":        {formOther},
+}
+
+// Language is a translation the player can pick
+type Language struct {
+	Code string // Name of the locale file, like en or de
+	Name string // Name of the language in the language itself
+}
+
+// message is a translated text, a message with plural forms picks one of them by a count
+type message struct {
+	text  string
+	forms map[string]string
+}
+
+func (m *message) UnmarshalJSON(data []byte) error {
+	if unmarshalErr := json.Unmarshal(data, &m.text); unmarshalErr == nil {
+		return nil
+	}
+	return json.Unmarshal(data, &m.forms)
+}
+
+// catalogue holds the messages of one language
+type catalogue struct {
+	Name     string             `json:"name"`
+	Plural   string             `json:"plural"`
+	Messages map[string]message `json:"messages"`
+}
+
+// Messages looks up the on-screen texts in the language the player picked
+type Messages struct {
+	catalogues map[string]*catalogue
+	languages  []Language
+	language   string
+}
+
+// Load parses and validates every locale file in the directory, the default language must be one of them
+func Load(files fs.FS) (*Messages, error) {
+	names, globErr := fs.Glob(files, "*.json")
+	if globErr != nil {
+		return nil, globErr
+	}
+	m := &Messages{
+		catalogues: make(map[string]*catalogue, len(names)),
+		language:   DefaultLanguage,
+	}
+	for _, name := range names {
+		data, readErr := fs.ReadFile(files, name)
+		if readErr != nil {
+			return nil, readErr
+		}
+		var c catalogue
+		if unmarshalErr := json.Unmarshal(data, &c); unmarshalErr != nil {
+			return nil, fmt.Errorf("locale %s: %w", name, unmarshalErr)
+		}
+		if c.Name == "" {
+			return nil, fmt.Errorf("locale %s has no name", name)
+		}
+		forms, ok := ruleForms[c.Plural]
+		if !ok {
+			return nil, fmt.Errorf("locale %s has an unknown plural rule %q", name, c.Plural)
+		}
+		for key, msg := range c.Messages {
+			if msg.forms == nil {
+				continue
+			}
+			for _, form := range forms {
+				if _, ok := msg.forms[form]; !ok {
+					return nil, fmt.Errorf("message %q of locale %s has no %q form", key, name, form)
+				}
+			}
+		}
+		code := strings.TrimSuffix(path.Base(name), ".json")
+		m.catalogues[code] = &c
+		m.languages = append(m.languages, Language{Code: code, Name: c.Name})
+	}
+
+	fallback, ok := m.catalogues[DefaultLanguage]
+	if !ok {
+		return nil, fmt.Errorf("no locale for the default language %q", DefaultLanguage)
+	}
+	// Every translated message must exist in the default language, this catches keys with typos
+	for code, c := range m.catalogues {
+		for key := range c.Messages {
+			if _, ok := fallback.Messages[key]; !ok {
+				return nil, fmt.Errorf("message %q of locale %s is not in the default locale", key, code)
+			}
+		}
+	}
+	slices.SortFunc(m.languages, func(a, b Language) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+	return m, nil
+}
+
+// Languages returns the translations the player can pick from
+func (m *Messages) Languages() []Language {
+	return m.languages
+}
+
+// Language returns the code of the language the texts are shown in
+func (m *Messages) Language() string {
+	return m.language
+}
+
+// SetLanguage shows the texts in the language with the code, unknown languages show the default language
+func (m *Messages) SetLanguage(code string) {
+	if _, ok := m.catalogues[code]; !ok {
+		code = DefaultLanguage
+	}
+	m.language = code
+}
+
+// lookup returns the message and the catalogue it was found in, falling back to the default language
+func (m *Messages) lookup(key string) (message, *catalogue, bool) {
+	for _, code := range []string{m.language, DefaultLanguage} {
+		c := m.catalogues[code]
+		if msg, ok := c.Messages[key]; ok {
+			return msg, c, true
+		}
+	}
+	return message{}, nil, false
+}
+
+// Text returns the message with the arguments put in for {0}, {1} and so on, an unknown key is returned as it is
+func (m *Messages) Text(key string, args ...any) string {
+	msg, c, ok := m.lookup(key)
+	if !ok {
+		return key
+	}
+	if msg.forms != nil {
+		// Without a count the most general form is used
+		forms := ruleForms[c.Plural]
+		return substitute(msg.forms[forms[len(forms)-1]], args)
+	}
+	return substitute(msg.text, args)
+}
+
+// Plural returns the form of the message that goes with the count, the count is put in for {0} and the arguments for {1} on
+func (m *Messages) Plural(key string, count int, args ...any) string {
+	msg, c, ok := m.lookup(key)
+	if !ok {
+		return key
+	}
+	args = append([]any{count}, args...)
+	if msg.forms == nil {
+		return substitute(msg.text, args)
+	}
+	return substitute(msg.forms[pluralRules[c.Plural](count)], args)
+}
+
+// substitute puts the arguments in for their placeholders
+func substitute(text string, args []any) string {
+	if len(args) == 0 {
+		return text
+	}
+	replacements := make([]string, 0, len(args)*2)
+	for i, arg := range args {
+		replacements = append(replacements, "{"+strconv.Itoa(i)+"}", fmt.Sprint(arg))
+	}
+	return strings.NewReplacer(replacements...).Replace(text)
+}
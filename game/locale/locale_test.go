@@ -0,0 +1,166 @@
+package locale
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/tejashwikalptaru/go.run/resources/locales"
+)
+
+func loadMessages(t *testing.T) *Messages {
+	t.Helper()
+	m, loadErr := Load(locales.Files)
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+	return m
+}
+
+func TestPlural(t *testing.T) {
+	m := loadMessages(t)
+	tests := []struct {
+		language string
+		count    int
+		want     string
+	}{
+		{"en", 0, "0 coins"},
+		{"en", 1, "1 coin"},
+		{"en", 2, "2 coins"},
+		{"en", 21, "21 coins"},
+		{"de", 1, "1 Münze"},
+		{"de", 5, "5 Münzen"},
+		{"ja", 1, "1 コイン"},
+		{"ja", 100, "100 コイン"},
+		{"ru", 1, "1 монета"},
+		{"ru", 2, "2 монеты"},
+		{"ru", 4, "4 монеты"},
+		{"ru", 5, "5 монет"},
+		{"ru", 11, "11 монет"},
+		{"ru", 12, "12 монет"},
+		{"ru", 14, "14 монет"},
+		{"ru", 21, "21 монета"},
+		{"ru", 22, "22 монеты"},
+		{"ru", 25, "25 монет"},
+		{"ru", 111, "111 монет"},
+		{"ru", 0, "0 монет"},
+	}
+	for _, tt := range tests {
+		m.SetLanguage(tt.language)
+		if got := m.Plural("shop.price", tt.count); got != tt.want {
+			t.Errorf("%s: Plural(shop.price, %d) = %q, want %q", tt.language, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestPluralArguments(t *testing.T) {
+	m := loadMessages(t)
+	tests := []struct {
+		language string
+		want     string
+	}{
+		{"en", "2 coins (1/2)"},
+		{"ru", "2 монеты (1/2)"},
+	}
+	for _, tt := range tests {
+		m.SetLanguage(tt.language)
+		if got := m.Plural("shop.priceOwned", 2, 1, 2); got != tt.want {
+			t.Errorf("%s: Plural(shop.priceOwned) = %q, want %q", tt.language, got, tt.want)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	m := loadMessages(t)
+	tests := []struct {
+		language string
+		key      string
+		args     []any
+		want     string
+	}{
+		{"en", "hud.level", []any{3}, "LEVEL 3"},
+		{"de", "summary.coins", []any{12, 340}, "Verdiente Münzen: 12 (Geldbörse: 340)"},
+		{"ja", "hud.level", []any{3}, "レベル 3"},
+		{"ru", "summary.coins", []any{12, 340}, "Заработано монет: 12 (Кошелёк: 340)"},
+		{"en", "summary.coins", []any{12}, "Coins earned: 12 (Wallet: {1})"}, // Missing arguments keep their placeholder
+		{"en", "shop.price", nil, "{0} coins"},                               // Without a count the most general form is used
+		{"ru", "shop.price", nil, "{0} монет"},
+		{"en", "no.such.key", nil, "no.such.key"},
+	}
+	for _, tt := range tests {
+		m.SetLanguage(tt.language)
+		if got := m.Text(tt.key, tt.args...); got != tt.want {
+			t.Errorf("%s: Text(%s) = %q, want %q", tt.language, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestFallback(t *testing.T) {
+	files := fstest.MapFS{
+		"en.json": {Data: []byte(`{"name": "English", "plural": "one-other", "messages": {
+			"greeting": "Hello {0}",
+			"apples": {"one": "{0} apple", "other": "{0} apples"}
+		}}`)},
+		"ru.json": {Data: []byte(`{"name": "Русский", "plural": "one-few-many", "messages": {
+			"greeting": "Привет {0}"
+		}}`)},
+	}
+	m, loadErr := Load(files)
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+
+	m.SetLanguage("ru")
+	if got := m.Text("greeting", "Ann"); got != "Привет Ann" {
+		t.Errorf("translated message = %q", got)
+	}
+	// A message missing from the translation is taken from the default language with its plural rule
+	if got := m.Plural("apples", 3); got != "3 apples" {
+		t.Errorf("missing translation = %q, want the English one", got)
+	}
+
+	// Unknown languages show the default language
+	m.SetLanguage("xx")
+	if m.Language() != DefaultLanguage {
+		t.Errorf("language = %q, want %q", m.Language(), DefaultLanguage)
+	}
+	if got := m.Text("greeting", "Ann"); got != "Hello Ann" {
+		t.Errorf("unknown language = %q", got)
+	}
+}
+
+func TestLoadRejectsBrokenLocales(t *testing.T) {
+	en := `{"name": "English", "plural": "one-other", "messages": {"greeting": "Hello"}}`
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+	}{
+		{"no default", fstest.MapFS{"de.json": {Data: []byte(`{"name": "Deutsch", "plural": "one-other", "messages": {}}`)}}},
+		{"no name", fstest.MapFS{"en.json": {Data: []byte(`{"plural": "one-other", "messages": {}}`)}}},
+		{"unknown plural rule", fstest.MapFS{"en.json": {Data: []byte(`{"name": "English", "plural": "two", "messages": {}}`)}}},
+		{"missing plural form", fstest.MapFS{
+			"en.json": {Data: []byte(en)},
+			"ru.json": {Data: []byte(`{"name": "Русский", "plural": "one-few-many", "messages": {"greeting": {"one": "a", "many": "b"}}}`)},
+		}},
+		{"key not in the default", fstest.MapFS{
+			"en.json": {Data: []byte(en)},
+			"de.json": {Data: []byte(`{"name": "Deutsch", "plural": "one-other", "messages": {"greting": "Hallo"}}`)},
+		}},
+	}
+	for _, tt := range tests {
+		if _, loadErr := Load(tt.files); loadErr == nil {
+			t.Errorf("%s: loaded without an error", tt.name)
+		}
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	m := loadMessages(t)
+	var codes []string
+	for _, language := range m.Languages() {
+		codes = append(codes, language.Code)
+	}
+	if want := []string{"de", "en", "ja", "ru"}; !slices.Equal(codes, want) {
+		t.Errorf("languages = %v, want %v", codes, want)
+	}
+}
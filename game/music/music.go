@@ -3,9 +3,11 @@ package music
 import (
 	"bytes"
 	"fmt"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
+	"github.com/tejashwikalptaru/go.run/game/event"
 	"github.com/tejashwikalptaru/go.run/resources/music"
 )
 
@@ -16,6 +18,7 @@ type Manager struct {
 	backgroundSound *audio.Player
 	jumpSound       *audio.Player
 	collisionSound  *audio.Player
+	coinSound       *audio.Player
 }
 
 func NewMusicManager() (*Manager, error) {
@@ -61,14 +64,36 @@ func NewMusicManager() (*Manager, error) {
 		return nil, collisionPlayerErr
 	}
 
+	// Generate the coin pickup sound, a short rising blip
+	coinPlayer := audioContext.NewPlayerFromBytes(newTone([]note{
+		{frequency: 988, duration: 60 * time.Millisecond},
+		{frequency: 1319, duration: 180 * time.Millisecond},
+	}, 0.2))
+
 	return &Manager{
 		audioContext:    audioContext,
 		backgroundSound: bgMusicPlayer,
 		jumpSound:       jumpPlayer,
 		collisionSound:  collisionPlayer,
+		coinSound:       coinPlayer,
 	}, nil
 }
 
+// Listen plays the sound effects for the game events
+func (m *Manager) Listen(events *event.Bus) {
+	event.Subscribe(events, func(event.Jumped) {
+		m.PlayJumpSound()
+	})
+	event.Subscribe(events, func(e event.Collision) {
+		if !e.PowerUp {
+			m.PlayCollisionSound()
+		}
+	})
+	event.Subscribe(events, func(event.CoinsCollected) {
+		m.PlayCoinSound()
+	})
+}
+
 // PlayBackground starts the background music if it is not already playing
 func (m *Manager) PlayBackground() {
 	if !m.backgroundSound.IsPlaying() {
@@ -111,3 +136,13 @@ func (m *Manager) PlayCollisionSound() {
 	}
 	m.collisionSound.Play()
 }
+
+// PlayCoinSound plays the coin pickup sound effect
+func (m *Manager) PlayCoinSound() {
+	// Rewind ensures the coin sound starts from the beginning
+	err := m.coinSound.Rewind()
+	if err != nil {
+		fmt.Printf("failed to rewind coin sound: %v", err)
+	}
+	m.coinSound.Play()
+}
@@ -0,0 +1,33 @@
+package music
+
+import (
+	"math"
+	"time"
+)
+
+// note is a single square wave tone
+type note struct {
+	frequency float64
+	duration  time.Duration
+}
+
+// newTone renders the notes one after the other as 16-bit stereo PCM, each note fades out towards its end
+func newTone(notes []note, volume float64) []byte {
+	var pcm []byte
+	for _, n := range notes {
+		samples := int(n.duration.Seconds() * sampleRate)
+		for i := 0; i < samples; i++ {
+			t := float64(i) / sampleRate
+			value := volume
+			if math.Sin(2*math.Pi*n.frequency*t) < 0 {
+				value = -volume
+			}
+			value *= 1 - float64(i)/float64(samples) // Linear fade out
+
+			sample := int16(value * math.MaxInt16)
+			// Same sample for the left and the right channel, little endian
+			pcm = append(pcm, byte(sample), byte(sample>>8), byte(sample), byte(sample>>8))
+		}
+	}
+	return pcm
+}
@@ -0,0 +1,145 @@
+package particle
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Shape is how a particle is drawn
+type Shape int
+
+const (
+	ShapeDot    Shape = iota // A square that shrinks or grows over its life
+	ShapeStreak              // A line along the direction the particle moves, like a rain drop
+)
+
+// Config describes the particles an emitter spawns
+type Config struct {
+	StartColor   color.RGBA // Color at birth, the color blends to EndColor over the particle's life
+	EndColor     color.RGBA
+	X, Y         float64 // Top left of the area particles are spawned in
+	Width        float64
+	Height       float64
+	MinVelocityX float64
+	MaxVelocityX float64
+	MinVelocityY float64
+	MaxVelocityY float64
+	Gravity      float64 // Added to the vertical velocity every frame
+	Rate         float64 // Particles spawned per frame, fractions add up over frames
+	StartSize    float64
+	EndSize      float64
+	Sway         float64 // Sideways wobble, like snow flakes drifting down
+	MinLife      int     // Frames a particle lives
+	MaxLife      int
+	Shape        Shape
+}
+
+type particleItem struct {
+	x, y   float64
+	vx, vy float64
+	phase  float64 // Phase of the sway
+	age    int
+	life   int
+}
+
+// Emitter spawns, moves and draws particles
+type Emitter struct {
+	rng       *rand.Rand
+	particles []particleItem
+	config    Config
+	pending   float64 // Fraction of a particle left over from the previous frames
+	wind      float64
+}
+
+func NewEmitter(config Config, rng *rand.Rand) *Emitter {
+	return &Emitter{
+		config: config,
+		rng:    rng,
+	}
+}
+
+// SetWind pushes every particle sideways, negative values blow to the left
+func (e *Emitter) SetWind(wind float64) {
+	e.wind = wind
+}
+
+// Burst spawns a number of particles at once
+func (e *Emitter) Burst(count int) {
+	for i := 0; i < count; i++ {
+		e.spawn()
+	}
+}
+
+// Clear removes all particles
+func (e *Emitter) Clear() {
+	e.particles = nil
+	e.pending = 0
+}
+
+// spawn adds a particle somewhere in the spawn area
+func (e *Emitter) spawn() {
+	c := e.config
+	e.particles = append(e.particles, particleItem{
+		x:     c.X + e.rng.Float64()*c.Width,
+		y:     c.Y + e.rng.Float64()*c.Height,
+		vx:    c.MinVelocityX + e.rng.Float64()*(c.MaxVelocityX-c.MinVelocityX),
+		vy:    c.MinVelocityY + e.rng.Float64()*(c.MaxVelocityY-c.MinVelocityY),
+		phase: e.rng.Float64() * 2 * math.Pi,
+		life:  c.MinLife + e.rng.Intn(max(c.MaxLife-c.MinLife, 0)+1),
+	})
+}
+
+func (e *Emitter) Update() {
+	e.pending += e.config.Rate
+	for ; e.pending >= 1; e.pending-- {
+		e.spawn()
+	}
+
+	// Move the particles and drop the ones that died
+	alive := e.particles[:0]
+	for _, p := range e.particles {
+		p.age++
+		if p.age >= p.life {
+			continue
+		}
+		p.vy += e.config.Gravity
+		p.x += p.vx + e.wind + e.config.Sway*math.Sin(float64(p.age)*0.05+p.phase)
+		p.y += p.vy
+		alive = append(alive, p)
+	}
+	e.particles = alive
+}
+
+func (e *Emitter) Draw(screen *ebiten.Image) {
+	c := e.config
+	for _, p := range e.particles {
+		t := float64(p.age) / float64(p.life)
+		clr := blend(c.StartColor, c.EndColor, t)
+		size := float32(c.StartSize + (c.EndSize-c.StartSize)*t)
+		if c.Shape == ShapeStreak {
+			// Trail behind the particle along its direction of travel
+			vx, vy := p.vx+e.wind, p.vy
+			vector.StrokeLine(screen, float32(p.x), float32(p.y), float32(p.x-vx*1.5), float32(p.y-vy*1.5), size, clr, false)
+			continue
+		}
+		vector.DrawFilledRect(screen, float32(p.x)-size/2, float32(p.y)-size/2, size, size, clr, false)
+	}
+}
+
+// blend mixes two colors and returns the result premultiplied by its alpha
+func blend(from, to color.RGBA, t float64) color.RGBA {
+	mix := func(a, b uint8) float64 {
+		return float64(a) + (float64(b)-float64(a))*t
+	}
+	alpha := mix(from.A, to.A) / 255
+	return color.RGBA{
+		R: uint8(mix(from.R, to.R) * alpha),
+		G: uint8(mix(from.G, to.G) * alpha),
+		B: uint8(mix(from.B, to.B) * alpha),
+		A: uint8(alpha * 255),
+	}
+}
@@ -0,0 +1,134 @@
+package pickup
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tejashwikalptaru/go.run/game/character"
+	"github.com/tejashwikalptaru/go.run/game/event"
+)
+
+type coinItem struct {
+	xPosition float64
+	yPosition float64
+	speed     float64
+	collected bool
+}
+
+// Coins manages the coins placed along the track and the ones collected during a run
+type Coins struct {
+	player    *character.Player
+	events    *event.Bus
+	image     *ebiten.Image
+	coins     []coinItem
+	groundY   float64
+	radius    float64
+	spacing   float64
+	ticks     int
+	collected int
+}
+
+func NewCoins(groundY float64, player *character.Player, events *event.Bus) *Coins {
+	radius := 8.0
+
+	// Render the coin once so that drawing only needs to scale it
+	size := int(radius * 2)
+	img := ebiten.NewImage(size, size)
+	vector.DrawFilledCircle(img, float32(radius), float32(radius), float32(radius), color.RGBA{R: 200, G: 140, B: 0, A: 255}, true)
+	vector.DrawFilledCircle(img, float32(radius), float32(radius), float32(radius-2), color.RGBA{R: 255, G: 210, B: 40, A: 255}, true)
+
+	return &Coins{
+		player:  player,
+		events:  events,
+		image:   img,
+		groundY: groundY,
+		radius:  radius,
+		spacing: 30,
+	}
+}
+
+// Clear removes all coins from the track
+func (c *Coins) Clear() {
+	c.coins = nil
+}
+
+// Reset clears the track and the coins collected during the run
+func (c *Coins) Reset() {
+	c.Clear()
+	c.collected = 0
+}
+
+// Collected returns the number of coins collected during the run
+func (c *Coins) Collected() int {
+	return c.collected
+}
+
+// PlaceLine places a line of coins at running height between startX and endX
+func (c *Coins) PlaceLine(startX, endX, speed float64) {
+	y := c.groundY - c.player.Height()/2
+	for x := startX; x <= endX; x += c.spacing {
+		c.coins = append(c.coins, coinItem{xPosition: x, yPosition: y, speed: speed})
+	}
+}
+
+// PlaceArc places coins along a jump arc centered on centerX, so that a well-timed jump collects all of them
+func (c *Coins) PlaceArc(centerX, halfWidth, height, speed float64) {
+	baseY := c.groundY - c.player.Height()/2
+	for x := centerX - halfWidth; x <= centerX+halfWidth; x += c.spacing {
+		// Parabola through both ends of the jump with its peak at the center
+		t := (x - centerX) / halfWidth
+		y := baseY - height*(1-t*t)
+		c.coins = append(c.coins, coinItem{xPosition: x, yPosition: y, speed: speed})
+	}
+}
+
+// collides checks if the player touches a coin
+func (c *Coins) collides(coin *coinItem) bool {
+	// Player's collision boundaries
+	playerLeft := c.player.XPosition() + c.player.CollisionLeft()
+	playerRight := playerLeft + c.player.CollisionWidth()
+	playerTop := c.player.YPosition() + c.player.CollisionTop()
+	playerBottom := playerTop + c.player.CollisionHeight()
+
+	xOverlap := playerRight > coin.xPosition-c.radius && playerLeft < coin.xPosition+c.radius
+	yOverlap := playerBottom > coin.yPosition-c.radius && playerTop < coin.yPosition+c.radius
+	return xOverlap && yOverlap
+}
+
+// Update moves the coins along with the track and publishes the coins picked up in this frame
+func (c *Coins) Update() {
+	c.ticks++
+	picked := 0
+	var filtered []coinItem
+	for _, coin := range c.coins {
+		coin.xPosition -= coin.speed
+		if !coin.collected && c.collides(&coin) {
+			coin.collected = true
+			picked++
+		}
+		// Keep coins that are still on the screen and not collected yet
+		if !coin.collected && coin.xPosition > -c.radius {
+			filtered = append(filtered, coin)
+		}
+	}
+	c.coins = filtered
+	if picked > 0 {
+		c.collected += picked
+		event.Publish(c.events, event.CoinsCollected{Count: picked})
+	}
+}
+
+// Draw renders the coins through the view of the camera, spinning around their vertical axis
+func (c *Coins) Draw(screen *ebiten.Image, view ebiten.GeoM) {
+	spin := math.Abs(math.Cos(float64(c.ticks) * 0.08))
+	for _, coin := range c.coins {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(-c.radius, -c.radius)
+		op.GeoM.Scale(max(spin, 0.15), 1) // Never draw the coin fully edge-on
+		op.GeoM.Translate(coin.xPosition, coin.yPosition)
+		op.GeoM.Concat(view)
+		screen.DrawImage(c.image, op)
+	}
+}
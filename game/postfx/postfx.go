@@ -0,0 +1,98 @@
+package postfx
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/tejashwikalptaru/go.run/resources/shaders"
+)
+
+// Effect is a shader the finished frame can be passed through
+type Effect string
+
+const (
+	EffectGrading    Effect = "grading"    // Tints the frame with the color grade of the scene
+	EffectBloom      Effect = "bloom"      // Bright parts glow
+	EffectAberration Effect = "aberration" // Red and blue drift apart towards the edges
+	EffectVignette   Effect = "vignette"   // Darkens the corners
+	EffectCRT        Effect = "crt"        // Scanlines and the curve of an old screen
+)
+
+// Effects are all the effects in the order they are applied
+var Effects = []Effect{EffectGrading, EffectBloom, EffectAberration, EffectVignette, EffectCRT}
+
+// Grade is the color grading of a scene
+type Grade struct {
+	Tint       [3]float32
+	Saturation float32
+	Contrast   float32
+}
+
+// NeutralGrade leaves the colors as they are
+var NeutralGrade = Grade{Tint: [3]float32{1, 1, 1}, Saturation: 1, Contrast: 1}
+
+// Pipeline passes a frame through a chain of effects
+type Pipeline struct {
+	shaders map[Effect]*ebiten.Shader
+	buffers [2]*ebiten.Image // The effects take turns reading from one and writing to the other
+	width   int
+	height  int
+}
+
+func NewPipeline(width, height int) (*Pipeline, error) {
+	sources := map[Effect][]byte{
+		EffectGrading:    shaders.Grading,
+		EffectBloom:      shaders.Bloom,
+		EffectAberration: shaders.Aberration,
+		EffectVignette:   shaders.Vignette,
+		EffectCRT:        shaders.CRT,
+	}
+	p := &Pipeline{
+		shaders: make(map[Effect]*ebiten.Shader, len(sources)),
+		buffers: [2]*ebiten.Image{ebiten.NewImage(width, height), ebiten.NewImage(width, height)},
+		width:   width,
+		height:  height,
+	}
+	for effect, source := range sources {
+		shader, shaderErr := ebiten.NewShader(source)
+		if shaderErr != nil {
+			return nil, shaderErr
+		}
+		p.shaders[effect] = shader
+	}
+	return p, nil
+}
+
+// Apply passes the frame through the enabled effects and draws the result to dst
+func (p *Pipeline) Apply(dst, src *ebiten.Image, enabled map[string]bool, grade Grade) {
+	current := src
+	next := 0
+	for _, effect := range Effects {
+		if !enabled[string(effect)] {
+			continue
+		}
+		p.buffers[next].Clear()
+		p.Render(p.buffers[next], current, effect, grade)
+		current = p.buffers[next]
+		next = 1 - next
+	}
+	dst.DrawImage(current, nil)
+}
+
+// Render draws src to dst through a single effect, the grade is only used by the grading effect
+func (p *Pipeline) Render(dst, src *ebiten.Image, effect Effect, grade Grade) {
+	size := []float32{float32(p.width), float32(p.height)}
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Images[0] = src
+	switch effect {
+	case EffectGrading:
+		op.Uniforms = map[string]any{"Tint": grade.Tint[:], "Saturation": grade.Saturation, "Contrast": grade.Contrast}
+	case EffectBloom:
+		op.Uniforms = map[string]any{"Size": size, "Threshold": float32(0.7), "Strength": float32(0.8)}
+	case EffectAberration:
+		op.Uniforms = map[string]any{"Size": size, "Offset": float32(2)}
+	case EffectVignette:
+		op.Uniforms = map[string]any{"Size": size, "Strength": float32(0.45)}
+	case EffectCRT:
+		op.Uniforms = map[string]any{"Size": size, "Curvature": float32(0.03)}
+	}
+	dst.DrawRectShader(p.width, p.height, p.shaders[effect], op)
+}
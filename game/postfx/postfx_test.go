@@ -0,0 +1,216 @@
+package postfx
+
+import (
+	"image/color"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const testSize = 64
+
+// testRunner runs the tests inside the game loop, shaders can only be drawn with and images only read while it runs
+type testRunner struct {
+	m    *testing.M
+	code int
+}
+
+func (r *testRunner) Update() error {
+	r.code = r.m.Run()
+	return ebiten.Termination
+}
+
+func (r *testRunner) Draw(*ebiten.Image) {}
+
+func (r *testRunner) Layout(int, int) (int, int) {
+	return testSize, testSize
+}
+
+// headless is true when there is no display to open the window on, the tests are skipped then
+var headless bool
+
+func TestMain(m *testing.M) {
+	if !hasDisplay() {
+		headless = true
+		os.Exit(m.Run())
+	}
+	runner := &testRunner{m: m}
+	if runErr := ebiten.RunGameWithOptions(runner, &ebiten.RunGameOptions{InitUnfocused: true}); runErr != nil {
+		panic(runErr)
+	}
+	os.Exit(runner.code)
+}
+
+// hasDisplay returns false on systems drawing with X11 or Wayland when neither runs, like on a CI machine without xvfb-run
+func hasDisplay() bool {
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios", "android", "js":
+		return true
+	}
+	return os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+}
+
+// requireDisplay skips the test without a display, ebiten only gets a graphics device along with its window
+func requireDisplay(t *testing.T) {
+	t.Helper()
+	if headless {
+		t.Skip("no display, run the tests under xvfb-run to render the effects")
+	}
+}
+
+// newFrame returns a frame with the color of every pixel picked by fill
+func newFrame(fill func(x, y int) color.RGBA) *ebiten.Image {
+	pixels := make([]byte, 0, testSize*testSize*4)
+	for y := 0; y < testSize; y++ {
+		for x := 0; x < testSize; x++ {
+			clr := fill(x, y)
+			pixels = append(pixels, clr.R, clr.G, clr.B, clr.A)
+		}
+	}
+	img := ebiten.NewImage(testSize, testSize)
+	img.WritePixels(pixels)
+	return img
+}
+
+// render passes the frame through a single effect
+func render(t *testing.T, src *ebiten.Image, effect Effect, grade Grade) *ebiten.Image {
+	t.Helper()
+	pipeline, pipelineErr := NewPipeline(testSize, testSize)
+	if pipelineErr != nil {
+		t.Fatal(pipelineErr)
+	}
+	dst := ebiten.NewImage(testSize, testSize)
+	pipeline.Render(dst, src, effect, grade)
+	return dst
+}
+
+func at(img *ebiten.Image, x, y int) color.RGBA {
+	return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+}
+
+// near returns true if the channels differ by no more than the tolerance
+func near(a, b color.RGBA, tolerance int) bool {
+	diff := func(x, y uint8) int {
+		return max(int(x)-int(y), int(y)-int(x))
+	}
+	return diff(a.R, b.R) <= tolerance && diff(a.G, b.G) <= tolerance && diff(a.B, b.B) <= tolerance && diff(a.A, b.A) <= tolerance
+}
+
+func gray(value uint8) func(x, y int) color.RGBA {
+	return func(int, int) color.RGBA {
+		return color.RGBA{R: value, G: value, B: value, A: 255}
+	}
+}
+
+func TestVignetteDarkensTheCorners(t *testing.T) {
+	requireDisplay(t)
+	dst := render(t, newFrame(gray(200)), EffectVignette, NeutralGrade)
+
+	if center := at(dst, testSize/2, testSize/2); !near(center, color.RGBA{R: 200, G: 200, B: 200, A: 255}, 2) {
+		t.Errorf("center = %v, want it unchanged", center)
+	}
+	if corner := at(dst, 0, 0); corner.R >= 150 {
+		t.Errorf("corner = %v, want it darkened", corner)
+	}
+}
+
+func TestBloomMakesBrightPartsGlow(t *testing.T) {
+	requireDisplay(t)
+	// A white square in the middle of a black frame
+	src := newFrame(func(x, y int) color.RGBA {
+		if x >= 28 && x < 36 && y >= 28 && y < 36 {
+			return color.RGBA{R: 255, G: 255, B: 255, A: 255}
+		}
+		return color.RGBA{A: 255}
+	})
+	dst := render(t, src, EffectBloom, NeutralGrade)
+
+	if glow := at(dst, 26, 32); glow.R == 0 {
+		t.Errorf("next to the square = %v, want it to glow", glow)
+	}
+	if far := at(dst, 2, 2); !near(far, color.RGBA{A: 255}, 0) {
+		t.Errorf("far from the square = %v, want it black", far)
+	}
+
+	// Nothing is above the threshold of a dim frame, so it is left as it is
+	dim := render(t, newFrame(gray(100)), EffectBloom, NeutralGrade)
+	if clr := at(dim, testSize/2, testSize/2); !near(clr, color.RGBA{R: 100, G: 100, B: 100, A: 255}, 1) {
+		t.Errorf("dim frame = %v, want it unchanged", clr)
+	}
+}
+
+func TestCRTDrawsScanlinesAndCurves(t *testing.T) {
+	requireDisplay(t)
+	dst := render(t, newFrame(gray(200)), EffectCRT, NeutralGrade)
+
+	// Every other line is darker
+	even, odd := at(dst, testSize/2, testSize/2), at(dst, testSize/2, testSize/2+1)
+	if even.G == odd.G {
+		t.Errorf("neighbouring lines = %v and %v, want scanlines", even, odd)
+	}
+	// The curve pushes the corners off the glass
+	if corner := at(dst, 0, 0); !near(corner, color.RGBA{A: 255}, 0) {
+		t.Errorf("corner = %v, want it black", corner)
+	}
+}
+
+func TestAberrationDriftsChannelsApartAtTheEdges(t *testing.T) {
+	requireDisplay(t)
+	// Red and blue grow from left to right
+	src := newFrame(func(x, _ int) color.RGBA {
+		return color.RGBA{R: uint8(x * 4), G: 128, B: uint8(x * 4), A: 255}
+	})
+	dst := render(t, src, EffectAberration, NeutralGrade)
+
+	if center := at(dst, testSize/2, testSize/2); !near(center, at(src, testSize/2, testSize/2), 4) {
+		t.Errorf("center = %v, want it unchanged", center)
+	}
+	// On the left edge red is taken from further left and blue from further right
+	edge, original := at(dst, 4, testSize/2), at(src, 4, testSize/2)
+	if edge.R >= original.R || edge.B <= original.B || edge.G != original.G {
+		t.Errorf("left edge = %v from %v, want less red and more blue", edge, original)
+	}
+}
+
+func TestGrading(t *testing.T) {
+	requireDisplay(t)
+	src := newFrame(func(int, int) color.RGBA {
+		return color.RGBA{R: 200, G: 100, B: 50, A: 255}
+	})
+
+	tests := []struct {
+		name  string
+		grade Grade
+		check func(clr color.RGBA) bool
+	}{
+		{
+			name:  "neutral",
+			grade: NeutralGrade,
+			check: func(clr color.RGBA) bool { return near(clr, color.RGBA{R: 200, G: 100, B: 50, A: 255}, 1) },
+		},
+		{
+			name:  "red tint",
+			grade: Grade{Tint: [3]float32{1, 0, 0}, Saturation: 1, Contrast: 1},
+			check: func(clr color.RGBA) bool { return near(clr, color.RGBA{R: 200, A: 255}, 1) },
+		},
+		{
+			name:  "no saturation",
+			grade: Grade{Tint: [3]float32{1, 1, 1}, Saturation: 0, Contrast: 1},
+			check: func(clr color.RGBA) bool { return near(clr, color.RGBA{R: clr.R, G: clr.R, B: clr.R, A: 255}, 1) },
+		},
+		{
+			name:  "no contrast",
+			grade: Grade{Tint: [3]float32{1, 1, 1}, Saturation: 1, Contrast: 0},
+			check: func(clr color.RGBA) bool { return near(clr, color.RGBA{R: 128, G: 128, B: 128, A: 255}, 1) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if clr := at(render(t, src, EffectGrading, tt.grade), testSize/2, testSize/2); !tt.check(clr) {
+				t.Errorf("graded = %v", clr)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package profile
+
+import (
+	"slices"
+	"time"
+)
+
+// maxHighScores is the number of best runs kept per profile
+const maxHighScores = 5
+
+// HighScore is one of the best runs of a profile
+type HighScore struct {
+	Date  time.Time `json:"date"`
+	Score int       `json:"score"`
+	Level int       `json:"level"`
+}
+
+// AddHighScore keeps the run if it is one of the best, it returns true if it made the list
+func (p *Profile) AddHighScore(score, level int) bool {
+	if score <= 0 {
+		return false
+	}
+	// Runs with the same score keep the older one first
+	index := slices.IndexFunc(p.HighScores, func(h HighScore) bool {
+		return h.Score < score
+	})
+	if index == -1 {
+		index = len(p.HighScores)
+	}
+	if index >= maxHighScores {
+		return false
+	}
+	p.HighScores = slices.Insert(p.HighScores, index, HighScore{Date: time.Now(), Score: score, Level: level})
+	p.HighScores = p.HighScores[:min(len(p.HighScores), maxHighScores)]
+	return true
+}
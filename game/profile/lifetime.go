@@ -0,0 +1,49 @@
+package profile
+
+// Lifetime holds the totals of every run the player has played
+type Lifetime struct {
+	Deaths      map[string]int `json:"deaths"`                // Runs ended per obstacle type
+	OtherDeaths map[string]int `json:"otherDeaths,omitempty"` // Runs ended by a pit or the boss, they are never cleared so they have no lethality
+	Cleared     map[string]int `json:"cleared"`               // Obstacles cleared per type
+	Distance    float64        `json:"distance"`              // In meters
+	PlayTime    float64        `json:"playTime"`              // In seconds
+	Runs        int            `json:"runs"`
+	BestLevel   int            `json:"bestLevel"`
+	BestScore   int            `json:"bestScore"`
+	Jumps       int            `json:"jumps"`
+	NearMisses  int            `json:"nearMisses"`
+}
+
+// AddDeath counts a run ended by the obstacle type
+func (l *Lifetime) AddDeath(obstacleType string) {
+	if l.Deaths == nil {
+		l.Deaths = map[string]int{}
+	}
+	l.Deaths[obstacleType]++
+}
+
+// AddOtherDeath counts a run ended by something that can't be cleared, like a pit or the boss
+func (l *Lifetime) AddOtherDeath(cause string) {
+	if l.OtherDeaths == nil {
+		l.OtherDeaths = map[string]int{}
+	}
+	l.OtherDeaths[cause]++
+}
+
+// AddCleared counts the obstacles of a type cleared during a run
+func (l *Lifetime) AddCleared(obstacleType string, count int) {
+	if l.Cleared == nil {
+		l.Cleared = map[string]int{}
+	}
+	l.Cleared[obstacleType] += count
+}
+
+// Lethality returns the share of encounters with the obstacle type that ended a run
+func (l *Lifetime) Lethality(obstacleType string) float64 {
+	deaths := l.Deaths[obstacleType]
+	encounters := deaths + l.Cleared[obstacleType]
+	if encounters == 0 {
+		return 0
+	}
+	return float64(deaths) / float64(encounters)
+}
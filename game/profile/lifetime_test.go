@@ -0,0 +1,26 @@
+package profile
+
+import "testing"
+
+func TestLethality(t *testing.T) {
+	l := Lifetime{}
+	l.AddCleared("snake", 3)
+	l.AddDeath("snake")
+	l.AddDeath("hyena")
+	l.AddOtherDeath("pit")
+
+	tests := []struct {
+		obstacleType string
+		want         float64
+	}{
+		{"snake", 0.25},
+		{"hyena", 1},
+		{"vulture", 0},
+		{"pit", 0}, // Pits are not obstacles, they have no lethality
+	}
+	for _, tt := range tests {
+		if got := l.Lethality(tt.obstacleType); got != tt.want {
+			t.Errorf("Lethality(%q) = %v, want %v", tt.obstacleType, got, tt.want)
+		}
+	}
+}
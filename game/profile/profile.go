@@ -0,0 +1,351 @@
+package profile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	dirName         = "go.run"
+	profilesDirName = "profiles"
+	activeFileName  = "active"
+	legacyFileName  = "profile.json" // Save file used before there were multiple profiles
+	fileExt         = ".json"
+
+	// DefaultName is the name of the profile used when the player never picked one
+	DefaultName = "Player"
+
+	// MaxNameLength is the longest profile name that can be typed
+	MaxNameLength = 12
+
+	// checksumSalt makes it harder to recompute the checksum after editing the save file by hand
+	checksumSalt = "go.run/profile/v1"
+)
+
+var (
+	// ErrChecksumMismatch is returned when the saved profile was modified outside the game
+	ErrChecksumMismatch = errors.New("profile checksum mismatch")
+
+	// ErrInvalidName is returned for profile names that can't be used as a file name
+	ErrInvalidName = errors.New("invalid profile name")
+
+	// ErrExists is returned when creating a profile with a name that is already taken
+	ErrExists = errors.New("profile already exists")
+)
+
+// Profile is the player's progress that is kept between runs
+type Profile struct {
+	Achievements   map[string]int `json:"achievements"`
+	name           string
+	path           string
+	Skin           string      `json:"skin"`
+	Skins          []string    `json:"skins"`
+	Unlocked       []string    `json:"unlocked"`
+	HighScores     []HighScore `json:"highScores"`
+	Lifetime       Lifetime    `json:"lifetime"`
+	Settings       Settings    `json:"settings"`
+	Coins          int         `json:"coins"`
+	ExtraLives     int         `json:"extraLives"`
+	StartingShield bool        `json:"startingShield"`
+	TutorialDone   bool        `json:"tutorialCompleted"` // The tutorial was played through or skipped, Play no longer starts it
+}
+
+// envelope is the layout of the save file, the checksum covers the raw profile data
+type envelope struct {
+	Profile  json.RawMessage `json:"profile"`
+	Checksum string          `json:"checksum"`
+}
+
+// Dir returns the directory in the user's config directory where the game keeps its files
+func Dir() (string, error) {
+	configDir, configDirErr := os.UserConfigDir()
+	if configDirErr != nil {
+		return "", configDirErr
+	}
+	return filepath.Join(configDir, dirName), nil
+}
+
+// profilesDir returns the directory holding one save file per profile
+func profilesDir() (string, error) {
+	dir, dirErr := Dir()
+	if dirErr != nil {
+		return "", dirErr
+	}
+	return filepath.Join(dir, profilesDirName), nil
+}
+
+// ValidName returns true if the name can be used for a profile, names are used as file names
+func ValidName(name string) bool {
+	if name == "" || len(name) > MaxNameLength {
+		return false
+	}
+	for _, r := range name {
+		if !ValidNameRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidNameRune returns true if the character may be used in a profile name
+func ValidNameRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_'
+}
+
+// path returns the location of the save file of the named profile
+func path(name string) (string, error) {
+	if !ValidName(name) {
+		return "", ErrInvalidName
+	}
+	dir, dirErr := profilesDir()
+	if dirErr != nil {
+		return "", dirErr
+	}
+	return filepath.Join(dir, name+fileExt), nil
+}
+
+// List returns the names of all saved profiles
+func List() ([]string, error) {
+	if migrateErr := migrate(); migrateErr != nil {
+		return nil, migrateErr
+	}
+	dir, dirErr := profilesDir()
+	if dirErr != nil {
+		return nil, dirErr
+	}
+	entries, readErr := os.ReadDir(dir)
+	if errors.Is(readErr, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if readErr != nil {
+		return nil, readErr
+	}
+	var names []string
+	for _, entry := range entries {
+		name, isProfile := strings.CutSuffix(entry.Name(), fileExt)
+		if isProfile && !entry.IsDir() && ValidName(name) {
+			names = append(names, name)
+		}
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	return names, nil
+}
+
+// migrate moves the save file of a single-profile install into the profiles directory
+func migrate() error {
+	dir, dirErr := Dir()
+	if dirErr != nil {
+		return dirErr
+	}
+	legacyPath := filepath.Join(dir, legacyFileName)
+	if _, statErr := os.Stat(legacyPath); errors.Is(statErr, fs.ErrNotExist) {
+		return nil
+	}
+	profilePath, pathErr := path(DefaultName)
+	if pathErr != nil {
+		return pathErr
+	}
+	if _, statErr := os.Stat(profilePath); statErr == nil {
+		// Never overwrite a profile, the legacy file stays where it is
+		return nil
+	}
+	if mkdirErr := os.MkdirAll(filepath.Dir(profilePath), 0o755); mkdirErr != nil {
+		return mkdirErr
+	}
+	return os.Rename(legacyPath, profilePath)
+}
+
+// Active returns the name of the profile that was played last
+func Active() (string, error) {
+	if migrateErr := migrate(); migrateErr != nil {
+		return "", migrateErr
+	}
+	dir, dirErr := Dir()
+	if dirErr != nil {
+		return "", dirErr
+	}
+	data, readErr := os.ReadFile(filepath.Join(dir, activeFileName))
+	if errors.Is(readErr, fs.ErrNotExist) {
+		return DefaultName, nil
+	}
+	if readErr != nil {
+		return "", readErr
+	}
+	name := strings.TrimSpace(string(data))
+	if !ValidName(name) {
+		return DefaultName, nil
+	}
+	return name, nil
+}
+
+// SetActive remembers the profile to load the next time the game starts
+func SetActive(name string) error {
+	if !ValidName(name) {
+		return ErrInvalidName
+	}
+	dir, dirErr := Dir()
+	if dirErr != nil {
+		return dirErr
+	}
+	if mkdirErr := os.MkdirAll(dir, 0o755); mkdirErr != nil {
+		return mkdirErr
+	}
+	return os.WriteFile(filepath.Join(dir, activeFileName), []byte(name), 0o600)
+}
+
+// New returns an empty profile with the given name, it is saved to the user's config directory
+func New(name string) (*Profile, error) {
+	profilePath, pathErr := path(name)
+	if pathErr != nil {
+		return nil, pathErr
+	}
+	return &Profile{name: name, path: profilePath}, nil
+}
+
+// Create returns a new profile and saves it right away, the name must not be taken by another profile
+func Create(name string) (*Profile, error) {
+	names, listErr := List()
+	if listErr != nil {
+		return nil, listErr
+	}
+	// Names differing only in case would share a save file on some systems
+	if slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, name) }) {
+		return nil, ErrExists
+	}
+	profile, profileErr := New(name)
+	if profileErr != nil {
+		return nil, profileErr
+	}
+	return profile, profile.Save()
+}
+
+// Load reads the named profile from the user's config directory, a new profile is returned if none was saved yet
+func Load(name string) (*Profile, error) {
+	profile, profileErr := New(name)
+	if profileErr != nil {
+		return nil, profileErr
+	}
+
+	data, readErr := os.ReadFile(profile.path)
+	if errors.Is(readErr, fs.ErrNotExist) {
+		return profile, nil
+	}
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	var saved envelope
+	if unmarshalErr := json.Unmarshal(data, &saved); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	if checksum(saved.Profile) != saved.Checksum {
+		return nil, ErrChecksumMismatch
+	}
+	if unmarshalErr := json.Unmarshal(saved.Profile, profile); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return profile, nil
+}
+
+// SetAside renames the save file of the named profile so a new one can be started without losing it, it returns where the file was moved to
+func SetAside(name string) (string, error) {
+	profilePath, pathErr := path(name)
+	if pathErr != nil {
+		return "", pathErr
+	}
+	// The time keeps an earlier rejected save from being overwritten
+	asidePath := profilePath + "." + time.Now().Format("20060102-150405") + ".bak"
+	return asidePath, os.Rename(profilePath, asidePath)
+}
+
+// Name returns the name the profile is saved under
+func (p *Profile) Name() string {
+	return p.name
+}
+
+// checksum returns the salted hash of the profile data
+func checksum(data []byte) string {
+	sum := sha256.Sum256(append([]byte(checksumSalt), data...))
+	return hex.EncodeToString(sum[:])
+}
+
+// Save writes the profile to disk
+func (p *Profile) Save() error {
+	data, marshalErr := json.Marshal(p)
+	if marshalErr != nil {
+		return marshalErr
+	}
+	// The envelope is not indented, that would reformat the profile data and break its checksum
+	file, marshalErr := json.Marshal(envelope{
+		Profile:  data,
+		Checksum: checksum(data),
+	})
+	if marshalErr != nil {
+		return marshalErr
+	}
+	if mkdirErr := os.MkdirAll(filepath.Dir(p.path), 0o755); mkdirErr != nil {
+		return mkdirErr
+	}
+	return os.WriteFile(p.path, file, 0o600)
+}
+
+// AddCoins adds the coins earned during a run to the wallet
+func (p *Profile) AddCoins(coins int) {
+	p.Coins += coins
+}
+
+// Spend takes the price from the wallet, it returns false if there are not enough coins
+func (p *Profile) Spend(price int) bool {
+	if p.Coins < price {
+		return false
+	}
+	p.Coins -= price
+	return true
+}
+
+// OwnsSkin returns true if the skin was bought
+func (p *Profile) OwnsSkin(skin string) bool {
+	return slices.Contains(p.Skins, skin)
+}
+
+// AddSkin adds a bought skin to the profile
+func (p *Profile) AddSkin(skin string) {
+	if !p.OwnsSkin(skin) {
+		p.Skins = append(p.Skins, skin)
+	}
+}
+
+// AchievementProgress returns the lifetime progress of an achievement
+func (p *Profile) AchievementProgress(id string) int {
+	return p.Achievements[id]
+}
+
+// SetAchievementProgress stores the lifetime progress of an achievement
+func (p *Profile) SetAchievementProgress(id string, progress int) {
+	if p.Achievements == nil {
+		p.Achievements = map[string]int{}
+	}
+	p.Achievements[id] = progress
+}
+
+// IsUnlocked returns true if the achievement was reached
+func (p *Profile) IsUnlocked(id string) bool {
+	return slices.Contains(p.Unlocked, id)
+}
+
+// Unlock marks the achievement as reached
+func (p *Profile) Unlock(id string) {
+	if !p.IsUnlocked(id) {
+		p.Unlocked = append(p.Unlocked, id)
+	}
+}
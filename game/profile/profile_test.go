@@ -0,0 +1,133 @@
+package profile
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// useTempConfigDir points the user's config directory of every platform to a directory removed after the test
+func useTempConfigDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("APPDATA", dir)
+}
+
+func savedProfile(t *testing.T, name string) *Profile {
+	t.Helper()
+	p, createErr := Create(name)
+	if createErr != nil {
+		t.Fatal(createErr)
+	}
+	p.AddCoins(120)
+	p.AddSkin("ninja")
+	p.Skin = "ninja"
+	p.SetAchievementProgress("jumper", 7)
+	p.Lifetime.AddDeath("snake")
+	if saveErr := p.Save(); saveErr != nil {
+		t.Fatal(saveErr)
+	}
+	return p
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	useTempConfigDir(t)
+	saved := savedProfile(t, "Ann")
+
+	loaded, loadErr := Load("Ann")
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+	if loaded.Name() != "Ann" || loaded.Coins != 120 || loaded.Skin != "ninja" || !loaded.OwnsSkin("ninja") {
+		t.Errorf("loaded = %+v, want %+v", loaded, saved)
+	}
+	if got := loaded.AchievementProgress("jumper"); got != 7 {
+		t.Errorf("achievement progress = %d, want 7", got)
+	}
+	if got := loaded.Lifetime.Deaths["snake"]; got != 1 {
+		t.Errorf("snake deaths = %d, want 1", got)
+	}
+
+	// A profile that was never saved is new
+	fresh, loadErr := Load("Bob")
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+	if fresh.Coins != 0 || fresh.Name() != "Bob" {
+		t.Errorf("unsaved profile = %+v, want a new one", fresh)
+	}
+}
+
+func TestLoadRejectsTamperedProfiles(t *testing.T) {
+	useTempConfigDir(t)
+	savedProfile(t, "Ann")
+	profilePath, pathErr := path("Ann")
+	if pathErr != nil {
+		t.Fatal(pathErr)
+	}
+	data, readErr := os.ReadFile(profilePath)
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(data []byte) []byte
+	}{
+		{"edited profile", func(data []byte) []byte {
+			return bytes.Replace(data, []byte(`"coins":120`), []byte(`"coins":99999`), 1)
+		}},
+		{"edited checksum", func(data []byte) []byte {
+			return bytes.Replace(data, []byte(`"checksum":"`), []byte(`"checksum":"0`), 1)
+		}},
+	}
+	for _, tt := range tests {
+		tampered := tt.tamper(data)
+		if bytes.Equal(tampered, data) {
+			t.Fatalf("%s: the save file was not changed", tt.name)
+		}
+		if writeErr := os.WriteFile(profilePath, tampered, 0o600); writeErr != nil {
+			t.Fatal(writeErr)
+		}
+		if _, loadErr := Load("Ann"); !errors.Is(loadErr, ErrChecksumMismatch) {
+			t.Errorf("%s: Load error = %v, want %v", tt.name, loadErr, ErrChecksumMismatch)
+		}
+	}
+}
+
+func TestSetAside(t *testing.T) {
+	useTempConfigDir(t)
+	savedProfile(t, "Ann")
+	profilePath, pathErr := path("Ann")
+	if pathErr != nil {
+		t.Fatal(pathErr)
+	}
+	data, readErr := os.ReadFile(profilePath)
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+
+	asidePath, asideErr := SetAside("Ann")
+	if asideErr != nil {
+		t.Fatal(asideErr)
+	}
+	if filepath.Dir(asidePath) != filepath.Dir(profilePath) {
+		t.Errorf("set aside to %s, want it next to %s", asidePath, profilePath)
+	}
+	// The rejected file is kept as it was
+	if kept, readErr := os.ReadFile(asidePath); readErr != nil || !bytes.Equal(kept, data) {
+		t.Errorf("set aside file = %q, %v, want the save file", kept, readErr)
+	}
+	if _, statErr := os.Stat(profilePath); !os.IsNotExist(statErr) {
+		t.Errorf("save file still exists: %v", statErr)
+	}
+	// The backup is not listed as a profile
+	if names, listErr := List(); listErr != nil || slices.Contains(names, "Ann") {
+		t.Errorf("profiles = %v, %v, want Ann gone", names, listErr)
+	}
+}
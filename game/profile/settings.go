@@ -0,0 +1,28 @@
+package profile
+
+// MaxWindowScale is the largest whole multiple of the game's resolution the window can be scaled to
+const MaxWindowScale = 4
+
+// Settings are the player's options, every profile keeps its own
+type Settings struct {
+	NightDarkness bool            `json:"nightDarkness"`           // Limit the view to the runner's surroundings at night
+	WindowScale   int             `json:"windowScale,omitempty"`   // Whole multiple of the game's resolution the window is sized to
+	Fullscreen    bool            `json:"fullscreen,omitempty"`    // Fill the monitor, the game is letterboxed to keep its aspect ratio
+	PixelPerfect  bool            `json:"pixelPerfect,omitempty"`  // Only scale by whole multiples with sharp pixels, the rest is letterboxed
+	Effects       map[string]bool `json:"effects,omitempty"`       // Post-processing effects by name, like crt or bloom
+	ReducedMotion bool            `json:"reducedMotion,omitempty"` // No screen shake, hit-stop or zoom
+	Language      string          `json:"language,omitempty"`      // Code of the language the texts are shown in, like en or de
+}
+
+// Scale returns the window scale, profiles saved before there was one use 1
+func (s Settings) Scale() int {
+	return min(max(s.WindowScale, 1), MaxWindowScale)
+}
+
+// ToggleEffect turns the named post-processing effect on or off
+func (s *Settings) ToggleEffect(name string) {
+	if s.Effects == nil {
+		s.Effects = make(map[string]bool)
+	}
+	s.Effects[name] = !s.Effects[name]
+}
@@ -0,0 +1,210 @@
+package shop
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tejashwikalptaru/go.run/game/character"
+	"github.com/tejashwikalptaru/go.run/game/locale"
+	"github.com/tejashwikalptaru/go.run/game/profile"
+	"github.com/tejashwikalptaru/go.run/game/ui"
+)
+
+const (
+	titleTextSize = 32
+	itemTextSize  = 20
+	maxExtraLives = 2
+)
+
+type itemKind int
+
+const (
+	itemKindSkin itemKind = iota
+	itemKindLife
+	itemKindShield
+)
+
+// item is something that can be bought, its name is the message shop.<id>
+type item struct {
+	id    string
+	kind  itemKind
+	price int
+}
+
+// catalogue lists everything that can be bought in the shop
+var catalogue = []item{
+	{id: character.SkinClassic, kind: itemKindSkin},
+	{id: character.SkinCrimson, kind: itemKindSkin, price: 100},
+	{id: character.SkinForest, kind: itemKindSkin, price: 150},
+	{id: character.SkinOcean, kind: itemKindSkin, price: 200},
+	{id: character.SkinShadow, kind: itemKindSkin, price: 300},
+	{id: character.SkinGold, kind: itemKindSkin, price: 500},
+	{id: "life", kind: itemKindLife, price: 250},
+	{id: "shield", kind: itemKindShield, price: 300},
+}
+
+// Shop lets the player spend the wallet on skins and starting power-ups
+type Shop struct {
+	text         *ui.Text
+	messages     *locale.Messages
+	profile      *profile.Profile
+	player       *character.Player
+	message      string
+	screenWidth  float64
+	screenHeight float64
+	cursor       int
+	open         bool
+}
+
+func NewShop(screenWidth, screenHeight float64, uiText *ui.Text, messages *locale.Messages, playerProfile *profile.Profile, player *character.Player) *Shop {
+	return &Shop{
+		text:         uiText,
+		messages:     messages,
+		profile:      playerProfile,
+		player:       player,
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+	}
+}
+
+// SetProfile makes the shop sell to another profile
+func (s *Shop) SetProfile(playerProfile *profile.Profile) {
+	s.profile = playerProfile
+}
+
+// Open shows the shop
+func (s *Shop) Open() {
+	s.open = true
+	s.cursor = 0
+	s.message = ""
+}
+
+// IsOpen returns true while the shop is shown
+func (s *Shop) IsOpen() bool {
+	return s.open
+}
+
+// owned returns true if the item was bought and can't be bought again
+func (s *Shop) owned(it item) bool {
+	switch it.kind {
+	case itemKindSkin:
+		return it.price == 0 || s.profile.OwnsSkin(it.id)
+	case itemKindLife:
+		return s.profile.ExtraLives >= maxExtraLives
+	default:
+		return s.profile.StartingShield
+	}
+}
+
+// status returns the text shown next to an item
+func (s *Shop) status(it item) string {
+	switch {
+	case it.kind == itemKindSkin && s.profile.Skin == it.id,
+		it.kind == itemKindSkin && s.profile.Skin == "" && it.id == character.SkinClassic:
+		return s.messages.Text("shop.equipped")
+	case it.kind == itemKindLife && s.profile.ExtraLives > 0 && !s.owned(it):
+		return s.messages.Plural("shop.priceOwned", it.price, s.profile.ExtraLives, maxExtraLives)
+	case s.owned(it):
+		return s.messages.Text("shop.owned")
+	default:
+		return s.messages.Plural("shop.price", it.price)
+	}
+}
+
+// selectItem buys the item under the cursor, or equips it if it is a skin that was already bought
+func (s *Shop) selectItem() {
+	it := catalogue[s.cursor]
+	if s.owned(it) {
+		if it.kind == itemKindSkin {
+			s.profile.Skin = it.id
+			s.player.SetSkin(it.id)
+			s.save()
+		}
+		return
+	}
+	if !s.profile.Spend(it.price) {
+		s.message = s.messages.Text("shop.notEnough")
+		return
+	}
+
+	switch it.kind {
+	case itemKindSkin:
+		s.profile.AddSkin(it.id)
+		s.profile.Skin = it.id
+		s.player.SetSkin(it.id)
+	case itemKindLife:
+		s.profile.ExtraLives++
+	case itemKindShield:
+		s.profile.StartingShield = true
+	}
+	s.message = s.messages.Text("shop.bought", s.name(it))
+	s.save()
+}
+
+// name returns the name the item is listed under
+func (s *Shop) name(it item) string {
+	return s.messages.Text("shop." + it.id)
+}
+
+// save writes the purchases to disk
+func (s *Shop) save() {
+	if saveErr := s.profile.Save(); saveErr != nil {
+		fmt.Printf("failed to save profile: %v", saveErr)
+	}
+}
+
+func (s *Shop) Update() {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		s.open = false
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		s.cursor = (s.cursor + len(catalogue) - 1) % len(catalogue)
+		s.message = ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		s.cursor = (s.cursor + 1) % len(catalogue)
+		s.message = ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		s.selectItem()
+	}
+}
+
+func (s *Shop) Draw(screen *ebiten.Image) {
+	// Darken the game behind the shop
+	vector.DrawFilledRect(screen, 0, 0, float32(s.screenWidth), float32(s.screenHeight), color.RGBA{A: 200}, false)
+
+	// The page keeps a margin of 40 pixels to the edges of the screen
+	page := ui.Rect{Width: s.screenWidth, Height: s.screenHeight}.Inset(40)
+	s.text.Draw(screen, s.messages.Text("shop.title", s.profile.Coins), page.X, 20, ui.Style{
+		Size:  titleTextSize,
+		Color: color.RGBA{R: 255, G: 210, B: 40, A: 255},
+	})
+
+	for i, it := range catalogue {
+		style := ui.Style{Size: itemTextSize, Color: color.RGBA{R: 200, G: 200, B: 200, A: 255}}
+		prefix := "  "
+		if i == s.cursor {
+			style.Color = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+			prefix = "> "
+		}
+		// The name is on the left of the row and the price or status on its right
+		row := ui.Rect{X: page.X, Y: 80 + float64(i)*style.LineHeight(), Width: page.Width, Height: style.LineHeight()}
+		s.text.DrawIn(screen, prefix+s.name(it), row, style)
+		style.Align = ui.AlignEnd
+		s.text.DrawIn(screen, s.status(it), row, style)
+	}
+
+	hint := s.messages.Text("shop.hint")
+	if s.message != "" {
+		hint = s.message
+	}
+	s.text.DrawIn(screen, hint, page, ui.Style{
+		Size:   itemTextSize,
+		Color:  color.RGBA{R: 255, A: 255},
+		Align:  ui.AlignCenter,
+		VAlign: ui.AlignEnd,
+		Wrap:   true,
+	})
+}
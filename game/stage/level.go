@@ -1,33 +1,46 @@
 package stage
 
 import (
-	"fmt"
 	"image/color"
 	"time"
 
+	"github.com/tejashwikalptaru/go.run/game/locale"
+	"github.com/tejashwikalptaru/go.run/game/ui"
 	"github.com/tejashwikalptaru/go.run/resources/fonts"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	pointsPerClear  = 10
+	closeCallBonus  = 25
+	comboStep       = 5  // Clears needed to raise the multiplier by one
+	maxMultiplier   = 4  // Highest combo multiplier
+	comboGraceTicks = 20 // Frames after landing in which a late clear still counts for the jump
 )
 
 type Level struct {
 	countdownStart     time.Time
-	textFaceSource     *text.GoTextFaceSource
+	text               *ui.Text
+	messages           *locale.Messages
 	countdownAlpha     float64
 	countdown          int
 	levelJumpThreshold int
+	bossEvery          int
 	level              int
 	jumps              int
 	score              int
+	combo              int
+	comboGrace         int
 	screenWidth        float64
 	screenHeight       float64
 	isFirstLevel       bool
 	gameOver           bool
 	inLevelGreeting    bool
+	jumpCredited       bool // An obstacle was cleared during the jump in the air
 }
 
-func NewLevel(screenWidth, screenHeight float64, textFaceSource *text.GoTextFaceSource, levelJumpThreshold int) *Level {
+func NewLevel(screenWidth, screenHeight float64, uiText *ui.Text, messages *locale.Messages, levelJumpThreshold, bossEvery int) *Level {
 	return &Level{
 		gameOver:           false,
 		inLevelGreeting:    true,
@@ -36,10 +49,12 @@ func NewLevel(screenWidth, screenHeight float64, textFaceSource *text.GoTextFace
 		countdown:          3,
 		countdownAlpha:     1.0,
 		levelJumpThreshold: levelJumpThreshold,
+		bossEvery:          bossEvery,
 		level:              1,
 		jumps:              0,
 		score:              0,
-		textFaceSource:     textFaceSource,
+		text:               uiText,
+		messages:           messages,
 		screenWidth:        screenWidth,
 		screenHeight:       screenHeight,
 	}
@@ -53,9 +68,67 @@ func (l *Level) Score() int {
 	return l.score
 }
 
-func (l *Level) IncreaseScore() {
+// IncreaseScore adds the points for a cleared obstacle, multiplied by the combo, and returns the points added
+func (l *Level) IncreaseScore(closeCall bool) int {
 	l.jumps++
-	l.score += 10
+	l.combo++
+	l.jumpCredited = true
+	l.comboGrace = 0 // A late clear still belongs to the last jump
+
+	points := pointsPerClear
+	if closeCall {
+		points += closeCallBonus
+	}
+	points *= l.Multiplier()
+	l.score += points
+	return points
+}
+
+// Combo returns the number of obstacles cleared in a row
+func (l *Level) Combo() int {
+	return l.combo
+}
+
+// Multiplier returns the current score multiplier, it grows with the combo
+func (l *Level) Multiplier() int {
+	return min(1+l.combo/comboStep, maxMultiplier)
+}
+
+// BreakCombo resets the combo, like when the player gets hit
+func (l *Level) BreakCombo() {
+	l.combo = 0
+	l.comboGrace = 0
+}
+
+// Jumped is called when the player takes off, a clear before it, like a late one after the last landing, doesn't count for this jump
+func (l *Level) Jumped() {
+	l.jumpCredited = false
+}
+
+// Landed is called when the player lands, a jump that cleared nothing breaks the combo
+func (l *Level) Landed() {
+	if l.jumpCredited {
+		l.jumpCredited = false
+		return
+	}
+	if l.combo > 0 {
+		l.comboGrace = comboGraceTicks
+	}
+}
+
+// Number returns the current level number
+func (l *Level) Number() int {
+	return l.level
+}
+
+// HasBoss returns true when a boss has to be beaten at the end of the current level
+func (l *Level) HasBoss() bool {
+	return l.bossEvery > 0 && l.level%l.bossEvery == 0
+}
+
+// Progress returns the share of the jumps needed to clear the level, from 0 to 1
+func (l *Level) Progress() float64 {
+	return min(float64(l.jumps)/float64(l.levelJumpThreshold), 1)
 }
 
 func (l *Level) Clear() bool {
@@ -73,6 +146,13 @@ func (l *Level) Next() {
 	l.countdownStart = time.Now()
 }
 
+// Advance moves on to the next level without a countdown, endless runs never stop
+func (l *Level) Advance() {
+	l.isFirstLevel = false
+	l.level++
+	l.jumps = 0
+}
+
 // handleCountdown manages the countdown before each stage
 func (l *Level) handleCountdown() {
 	elapsed := time.Since(l.countdownStart).Seconds()
@@ -95,30 +175,41 @@ func (l *Level) Update() {
 		l.handleCountdown()
 		return
 	}
+
+	// Break the combo once the grace time after an empty jump is over
+	if l.comboGrace > 0 {
+		l.comboGrace--
+		if l.comboGrace == 0 {
+			l.combo = 0
+		}
+	}
 }
 
 func (l *Level) Draw(screen *ebiten.Image) {
 	// If we're in the stage greeting phase, show the greeting and countdown
 	if l.inLevelGreeting {
-		msg := fmt.Sprintf("Level %d", l.level)
-		op := &text.DrawOptions{}
-		op.GeoM.Translate(l.screenWidth/3, l.screenHeight/6)
-		op.ColorScale.ScaleWithColor(color.RGBA{R: 255, G: 255, B: 255, A: 255})
-		text.Draw(screen, msg, &text.GoTextFace{
-			Source: l.textFaceSource,
-			Size:   fonts.DefaultTextSize,
-		}, op)
+		msg := l.messages.Text("level.greeting", l.level)
+		if l.HasBoss() {
+			msg = l.messages.Text("level.bossGreeting", l.level)
+		}
+		// The greeting ends and the countdown starts a quarter down the screen, both centered across it
+		greetingArea := ui.Rect{Width: l.screenWidth, Height: l.screenHeight / 4}
+		countdownArea := ui.Rect{Y: greetingArea.Height + 10, Width: l.screenWidth, Height: l.screenHeight / 4}
+		style := ui.Style{
+			Size:    fonts.DefaultTextSize,
+			Outline: color.RGBA{A: 200},
+			Align:   ui.AlignCenter,
+			VAlign:  ui.AlignEnd,
+			Wrap:    true,
+		}
+		l.text.DrawIn(screen, msg, greetingArea, style)
 
 		// Countdown logic: Fade-in/out based on alpha value
-		countdownText := fmt.Sprintf("Ready... %d", l.countdown)
-		op1 := &text.DrawOptions{}
-		op1.GeoM.Translate(l.screenWidth/3, l.screenHeight/3)
-		op1.ColorScale.ScaleAlpha(float32(l.countdownAlpha * 255))
-		op1.ColorScale.ScaleWithColor(color.RGBA{R: 255, G: 0, B: 0})
-		text.Draw(screen, countdownText, &text.GoTextFace{
-			Source: l.textFaceSource,
-			Size:   fonts.DefaultTextSize,
-		}, op1)
+		countdownText := l.messages.Text("level.ready", l.countdown)
+		style.Color = ui.Fade(color.RGBA{R: 255, A: 255}, l.countdownAlpha)
+		style.Outline = ui.Fade(color.RGBA{A: 200}, l.countdownAlpha)
+		style.VAlign = ui.AlignStart
+		l.text.DrawIn(screen, countdownText, countdownArea, style)
 
 		// Update alpha value for smooth fade in/out
 		l.countdownAlpha -= 0.05
@@ -0,0 +1,72 @@
+package stage
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/tejashwikalptaru/go.run/game/ui"
+)
+
+const (
+	popupTextSize = 20
+	popupFrames   = 45
+	popupRise     = 1.0
+)
+
+type popup struct {
+	message   string
+	clr       color.RGBA
+	xPosition float64
+	yPosition float64
+	ticks     int
+}
+
+// Popups are short messages, like the points scored, that float up and fade away
+type Popups struct {
+	text   *ui.Text
+	popups []popup
+}
+
+func NewPopups(uiText *ui.Text) *Popups {
+	return &Popups{
+		text: uiText,
+	}
+}
+
+// Add shows a new popup at the given position
+func (p *Popups) Add(x, y float64, message string, clr color.RGBA) {
+	p.popups = append(p.popups, popup{
+		message:   message,
+		clr:       clr,
+		xPosition: x,
+		yPosition: y,
+	})
+}
+
+// Reset removes all popups
+func (p *Popups) Reset() {
+	p.popups = nil
+}
+
+func (p *Popups) Update() {
+	var filtered []popup
+	for _, pp := range p.popups {
+		pp.ticks++
+		pp.yPosition -= popupRise
+		if pp.ticks < popupFrames {
+			filtered = append(filtered, pp)
+		}
+	}
+	p.popups = filtered
+}
+
+func (p *Popups) Draw(screen *ebiten.Image) {
+	for _, pp := range p.popups {
+		alpha := 1 - float64(pp.ticks)/popupFrames // Fade out while rising
+		p.text.Draw(screen, pp.message, pp.xPosition, pp.yPosition, ui.Style{
+			Size:    popupTextSize,
+			Color:   ui.Fade(pp.clr, alpha),
+			Outline: ui.Fade(color.RGBA{A: 200}, alpha), // The popups float over the busy scene
+		})
+	}
+}
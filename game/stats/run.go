@@ -0,0 +1,158 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/tejashwikalptaru/go.run/game/event"
+	"github.com/tejashwikalptaru/go.run/game/profile"
+)
+
+// pixelsPerMeter converts the distance scrolled on screen to meters
+const pixelsPerMeter = 20
+
+// Run holds the statistics of a single run
+type Run struct {
+	Cleared     map[string]int `json:"cleared"`
+	KilledBy    string         `json:"killedBy,omitempty"`
+	DeathSource string         `json:"deathSource,omitempty"` // Source of the collision that ended the run, like obstacle or hazard
+	Distance    float64        `json:"distance"`              // In meters
+	MaxSpeed    float64        `json:"maxSpeed"`              // In pixels per frame
+	TimeAlive   float64        `json:"timeAlive"`             // In seconds
+	Score       int            `json:"score"`
+	Earned      int            `json:"earned"`
+	Level       int            `json:"level"`
+	Jumps       int            `json:"jumps"`
+	NearMisses  int            `json:"nearMisses"`
+	Coins       int            `json:"coins"`
+}
+
+// TotalCleared returns the number of obstacles cleared of all types
+func (r *Run) TotalCleared() int {
+	total := 0
+	for _, count := range r.Cleared {
+		total += count
+	}
+	return total
+}
+
+// Tracker collects the statistics of the current run from the game events
+type Tracker struct {
+	run      *Run
+	ticks    int
+	practice bool // Nothing is collected, like in the tutorial
+}
+
+func NewTracker() *Tracker {
+	tracker := &Tracker{}
+	tracker.Start()
+	return tracker
+}
+
+// Start begins collecting the statistics of a new run
+func (t *Tracker) Start() {
+	t.run = &Run{
+		Cleared: map[string]int{},
+		Level:   1,
+	}
+	t.ticks = 0
+}
+
+// Run returns the statistics collected so far
+func (t *Tracker) Run() *Run {
+	return t.run
+}
+
+// SetPractice stops collecting statistics while the player practices, like in the tutorial
+func (t *Tracker) SetPractice(practice bool) {
+	t.practice = practice
+}
+
+// Listen collects the statistics from the game events, unless the run is practice
+func (t *Tracker) Listen(events *event.Bus) {
+	subscribe(t, events, func(event.Jumped) {
+		t.run.Jumps++
+	})
+	subscribe(t, events, func(e event.ObstacleCleared) {
+		t.run.Cleared[e.Type]++
+		if e.CloseCall {
+			t.run.NearMisses++
+		}
+	})
+	subscribe(t, events, func(e event.CoinsCollected) {
+		t.run.Coins += e.Count
+	})
+	subscribe(t, events, func(e event.LevelStarted) {
+		t.run.Level = e.Level
+	})
+	subscribe(t, events, func(e event.GameOver) {
+		t.run.Score = e.Score
+		t.run.Earned = e.Earned
+		t.run.KilledBy = e.KilledBy
+		t.run.DeathSource = e.Source
+	})
+}
+
+// subscribe registers a handler for the events of type T that leaves practice runs out
+func subscribe[T any](t *Tracker, events *event.Bus, handler func(T)) {
+	event.Subscribe(events, func(e T) {
+		if !t.practice {
+			handler(e)
+		}
+	})
+}
+
+// Update counts the time alive and the distance run at the current world speed, it is called every frame of play
+func (t *Tracker) Update(speed float64) {
+	if t.practice {
+		return
+	}
+	t.ticks++
+	t.run.TimeAlive = float64(t.ticks) / float64(ebiten.TPS())
+	t.run.Distance += speed / pixelsPerMeter
+	t.run.MaxSpeed = max(t.run.MaxSpeed, speed)
+}
+
+// Export writes the run statistics as JSON next to the profile and returns the file path
+func Export(run *Run) (string, error) {
+	dir, dirErr := profile.Dir()
+	if dirErr != nil {
+		return "", dirErr
+	}
+	data, marshalErr := json.MarshalIndent(run, "", "  ")
+	if marshalErr != nil {
+		return "", marshalErr
+	}
+	runsDir := filepath.Join(dir, "runs")
+	if mkdirErr := os.MkdirAll(runsDir, 0o755); mkdirErr != nil {
+		return "", mkdirErr
+	}
+	path := filepath.Join(runsDir, fmt.Sprintf("run-%s.json", time.Now().Format("20060102-150405")))
+	return path, os.WriteFile(path, data, 0o600)
+}
+
+// AddTo adds the run to the player's lifetime totals
+func (r *Run) AddTo(lifetime *profile.Lifetime) {
+	lifetime.Runs++
+	lifetime.Distance += r.Distance
+	lifetime.PlayTime += r.TimeAlive
+	lifetime.Jumps += r.Jumps
+	lifetime.NearMisses += r.NearMisses
+	lifetime.BestLevel = max(lifetime.BestLevel, r.Level)
+	lifetime.BestScore = max(lifetime.BestScore, r.Score)
+	for obstacleType, count := range r.Cleared {
+		lifetime.AddCleared(obstacleType, count)
+	}
+	switch {
+	case r.KilledBy == "":
+	case r.DeathSource == event.SourceHazard || r.DeathSource == event.SourceBoss:
+		// Pits and the boss are never cleared, they are kept apart from the lethality of the obstacles
+		lifetime.AddOtherDeath(r.KilledBy)
+	default:
+		lifetime.AddDeath(r.KilledBy)
+	}
+}
@@ -0,0 +1,131 @@
+package stats
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tejashwikalptaru/go.run/game/locale"
+	"github.com/tejashwikalptaru/go.run/game/ui"
+)
+
+const (
+	summaryTitleSize = 40
+	summaryTextSize  = 18
+	summaryHintSize  = 14
+	summaryPages     = 2
+)
+
+// Summary is the paged end-of-run screen showing the run statistics
+type Summary struct {
+	text         *ui.Text
+	messages     *locale.Messages
+	message      string
+	screenWidth  float64
+	screenHeight float64
+	page         int
+}
+
+func NewSummary(screenWidth, screenHeight float64, uiText *ui.Text, messages *locale.Messages) *Summary {
+	return &Summary{
+		text:         uiText,
+		messages:     messages,
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+	}
+}
+
+// Reset goes back to the first page
+func (s *Summary) Reset() {
+	s.page = 0
+	s.message = ""
+}
+
+// NextPage shows the next page
+func (s *Summary) NextPage() {
+	s.page = (s.page + 1) % summaryPages
+}
+
+// PrevPage shows the previous page
+func (s *Summary) PrevPage() {
+	s.page = (s.page + summaryPages - 1) % summaryPages
+}
+
+// SetMessage shows a message in the footer, like where the run was exported to
+func (s *Summary) SetMessage(message string) {
+	s.message = message
+}
+
+// lines returns the title and the lines of the current page
+func (s *Summary) lines(run *Run, wallet int) (string, []string) {
+	m := s.messages
+	if s.page == 0 {
+		killedBy := "-"
+		if run.KilledBy != "" {
+			killedBy = m.Text("obstacle." + run.KilledBy)
+		}
+		return m.Text("summary.run"), []string{
+			m.Text("summary.score", run.Score),
+			m.Text("summary.level", run.Level),
+			m.Text("summary.timeAlive", fmt.Sprintf("%.1f", run.TimeAlive)),
+			m.Text("summary.distance", fmt.Sprintf("%.0f", run.Distance)),
+			m.Text("summary.maxSpeed", fmt.Sprintf("%.0f", run.MaxSpeed)),
+			m.Text("summary.killedBy", killedBy),
+			m.Text("summary.coins", run.Earned, wallet),
+		}
+	}
+
+	lines := []string{
+		m.Text("summary.jumps", run.Jumps),
+		m.Text("summary.nearMisses", run.NearMisses),
+		m.Text("summary.cleared", run.TotalCleared()),
+	}
+	// List the obstacle types in a stable order
+	types := make([]string, 0, len(run.Cleared))
+	for t := range run.Cleared {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	for _, t := range types {
+		lines = append(lines, fmt.Sprintf("  %s: %d", m.Text("obstacle."+t), run.Cleared[t]))
+	}
+	return m.Text("summary.obstacles"), lines
+}
+
+func (s *Summary) Draw(screen *ebiten.Image, run *Run, wallet int) {
+	// Darken the game behind the summary
+	vector.DrawFilledRect(screen, 0, 0, float32(s.screenWidth), float32(s.screenHeight), color.RGBA{A: 160}, false)
+
+	// The title and the page name are centered at the top, the hint at the bottom wraps upwards
+	s.text.DrawIn(screen, s.messages.Text("summary.title"), ui.Rect{Y: 15, Width: s.screenWidth}, ui.Style{
+		Size:  summaryTitleSize,
+		Color: color.RGBA{R: 255, A: 255},
+		Align: ui.AlignCenter,
+	})
+
+	pageTitle, lines := s.lines(run, wallet)
+	s.text.DrawIn(screen, s.messages.Text("summary.page", pageTitle, s.page+1, summaryPages), ui.Rect{Y: 70, Width: s.screenWidth}, ui.Style{
+		Size:  summaryTextSize,
+		Color: color.RGBA{R: 255, G: 210, B: 40, A: 255},
+		Align: ui.AlignCenter,
+	})
+
+	lineStyle := ui.Style{Size: summaryTextSize}
+	for i, line := range lines {
+		s.text.Draw(screen, line, s.screenWidth/4, 105+float64(i)*lineStyle.LineHeight(), lineStyle)
+	}
+
+	footer := s.messages.Text("summary.hint")
+	if s.message != "" {
+		footer = s.message
+	}
+	s.text.DrawIn(screen, footer, ui.Rect{Y: s.screenHeight - 45, Width: s.screenWidth, Height: 45}.Inset(10), ui.Style{
+		Size:   summaryHintSize,
+		Color:  color.RGBA{R: 220, G: 220, B: 220, A: 255},
+		Align:  ui.AlignCenter,
+		VAlign: ui.AlignEnd,
+		Wrap:   true, // Exported file paths can be long
+	})
+}
@@ -0,0 +1,34 @@
+package title
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tejashwikalptaru/go.run/game/postfx"
+)
+
+// updateEffects toggles the effect of the pressed number key
+func (t *Title) updateEffects() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		t.page = pageSettings
+		return
+	}
+	for i, effect := range postfx.Effects {
+		if inpututil.IsKeyJustPressed(ebiten.Key1 + ebiten.Key(i)) {
+			t.profile.Settings.ToggleEffect(string(effect))
+			t.saveSettings()
+		}
+	}
+}
+
+// drawEffects lists the post-processing effects with the keys that turn them on and off
+func (t *Title) drawEffects(screen *ebiten.Image) {
+	t.drawHeading(screen, t.messages.Text("effects.title"))
+
+	for i, effect := range postfx.Effects {
+		// The effects are listed under the messages effects.<name>
+		name := t.messages.Text("effects." + string(effect))
+		t.text.Draw(screen, t.messages.Text("effects.entry", i+1, name, t.onOff(t.profile.Settings.Effects[string(effect)])), t.area().X, itemY(i), itemStyle)
+	}
+
+	t.drawHint(screen, t.messages.Text("common.back"))
+}
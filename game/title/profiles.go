@@ -0,0 +1,144 @@
+package title
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tejashwikalptaru/go.run/game/profile"
+)
+
+// profilePicker holds the state of the profiles page
+type profilePicker struct {
+	names   []string
+	input   []rune // Name typed for a new profile
+	message string
+	cursor  int
+	typing  bool
+}
+
+// openProfiles shows the saved profiles with the current one selected
+func (t *Title) openProfiles() {
+	names, listErr := profile.List()
+	if listErr != nil {
+		fmt.Printf("failed to list profiles: %v", listErr)
+	}
+	// A profile that was never saved is not on disk yet
+	if !slices.Contains(names, t.profile.Name()) {
+		names = append(names, t.profile.Name())
+	}
+	t.profiles = &profilePicker{
+		names:  names,
+		cursor: slices.Index(names, t.profile.Name()),
+	}
+	t.page = pageProfiles
+}
+
+// updateProfiles lets the player pick a profile or type the name of a new one
+func (t *Title) updateProfiles() {
+	p := t.profiles
+	if p.typing {
+		t.updateNameInput()
+		return
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		t.page = pageMenu
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		p.cursor = (p.cursor + len(p.names) - 1) % len(p.names)
+		p.message = ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		p.cursor = (p.cursor + 1) % len(p.names)
+		p.message = ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyN):
+		p.typing = true
+		p.input = nil
+		p.message = ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		name := p.names[p.cursor]
+		if name == t.profile.Name() {
+			t.page = pageMenu
+			return
+		}
+		if selectErr := t.selectProfile(name); selectErr != nil {
+			fmt.Printf("failed to load profile: %v", selectErr)
+			p.message = t.messages.Text("profiles.loadFailed", name)
+			return
+		}
+		t.page = pageMenu
+	}
+}
+
+// updateNameInput handles typing the name of a new profile
+func (t *Title) updateNameInput() {
+	p := t.profiles
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if profile.ValidNameRune(r) && len(p.input) < profile.MaxNameLength {
+			p.input = append(p.input, r)
+			p.message = ""
+		}
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		p.typing = false
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(p.input) > 0:
+		p.input = p.input[:len(p.input)-1]
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		t.createProfile(string(p.input))
+	}
+}
+
+// createProfile saves a new profile and switches to it
+func (t *Title) createProfile(name string) {
+	p := t.profiles
+	if !profile.ValidName(name) {
+		p.message = t.messages.Text("profiles.noName")
+		return
+	}
+	_, createErr := profile.Create(name)
+	if errors.Is(createErr, profile.ErrExists) {
+		p.message = t.messages.Text("profiles.exists", name)
+		return
+	}
+	if createErr == nil {
+		createErr = t.selectProfile(name)
+	}
+	if createErr != nil {
+		fmt.Printf("failed to create profile: %v", createErr)
+		p.message = t.messages.Text("profiles.createFailed", name)
+		return
+	}
+	t.page = pageMenu
+}
+
+func (t *Title) drawProfiles(screen *ebiten.Image) {
+	p := t.profiles
+
+	t.drawHeading(screen, t.messages.Text("profiles.title"))
+
+	for i, name := range p.names {
+		style := itemStyle
+		style.Color = color.RGBA{R: 200, G: 200, B: 200, A: 255}
+		prefix := "  "
+		if i == p.cursor && !p.typing {
+			style.Color = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+			prefix = "> "
+		}
+		if name == t.profile.Name() {
+			name = t.messages.Text("profiles.current", name)
+		}
+		t.text.Draw(screen, prefix+name, t.area().X, itemY(i), style)
+	}
+
+	hint := t.messages.Text("profiles.hint")
+	if p.typing {
+		hint = t.messages.Text("profiles.input", string(p.input))
+	}
+	if p.message != "" {
+		hint = p.message
+	}
+	t.drawHint(screen, hint)
+}
@@ -0,0 +1,111 @@
+package title
+
+import (
+	"image/color"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tejashwikalptaru/go.run/game/locale"
+	"github.com/tejashwikalptaru/go.run/game/profile"
+)
+
+// settingItem is an entry of the settings page, change runs when it is picked or changed with the arrow keys
+type settingItem struct {
+	label  func(t *Title) string
+	change func(t *Title)
+}
+
+var settingItems = []settingItem{
+	{
+		label:  func(t *Title) string { return t.messages.Text("settings.language", t.languageName()) },
+		change: func(t *Title) { t.profile.Settings.Language = t.nextLanguage() },
+	},
+	{
+		label: func(t *Title) string {
+			return t.messages.Text("settings.darkNights", t.onOff(t.profile.Settings.NightDarkness))
+		},
+		change: func(t *Title) { t.profile.Settings.NightDarkness = !t.profile.Settings.NightDarkness },
+	},
+	{
+		label: func(t *Title) string { return t.messages.Text("settings.windowScale", t.profile.Settings.Scale()) },
+		// Cycle through the window scales, the display applies the new size
+		change: func(t *Title) { t.profile.Settings.WindowScale = t.profile.Settings.Scale()%profile.MaxWindowScale + 1 },
+	},
+	{
+		label: func(t *Title) string {
+			return t.messages.Text("settings.fullscreen", t.onOff(t.profile.Settings.Fullscreen))
+		},
+		change: func(t *Title) { t.profile.Settings.Fullscreen = !t.profile.Settings.Fullscreen },
+	},
+	{
+		label: func(t *Title) string {
+			return t.messages.Text("settings.pixelPerfect", t.onOff(t.profile.Settings.PixelPerfect))
+		},
+		change: func(t *Title) { t.profile.Settings.PixelPerfect = !t.profile.Settings.PixelPerfect },
+	},
+	{
+		label: func(t *Title) string {
+			return t.messages.Text("settings.reducedMotion", t.onOff(t.profile.Settings.ReducedMotion))
+		},
+		change: func(t *Title) { t.profile.Settings.ReducedMotion = !t.profile.Settings.ReducedMotion },
+	},
+	{
+		label:  func(t *Title) string { return t.messages.Text("settings.effects") },
+		change: func(t *Title) { t.page = pageEffects },
+	},
+}
+
+// languageName returns the name of the language the texts are shown in
+func (t *Title) languageName() string {
+	for _, language := range t.messages.Languages() {
+		if language.Code == t.messages.Language() {
+			return language.Name
+		}
+	}
+	return t.messages.Language()
+}
+
+// nextLanguage returns the code of the language after the one the texts are shown in
+func (t *Title) nextLanguage() string {
+	languages := t.messages.Languages()
+	current := slices.IndexFunc(languages, func(language locale.Language) bool {
+		return language.Code == t.messages.Language()
+	})
+	next := languages[(current+1)%len(languages)].Code
+	t.messages.SetLanguage(next) // Show the settings page in the new language right away
+	return next
+}
+
+// updateSettings moves the selection with the arrow keys and changes the selected setting
+func (t *Title) updateSettings() {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		t.page = pageMenu
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp):
+		t.setting = (t.setting + len(settingItems) - 1) % len(settingItems)
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		t.setting = (t.setting + 1) % len(settingItems)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace) ||
+		inpututil.IsKeyJustPressed(ebiten.KeyLeft) || inpututil.IsKeyJustPressed(ebiten.KeyRight):
+		settingItems[t.setting].change(t)
+		t.saveSettings()
+	}
+}
+
+// drawSettings lists the settings with the selected one highlighted
+func (t *Title) drawSettings(screen *ebiten.Image) {
+	t.drawHeading(screen, t.messages.Text("settings.title", t.profile.Name()))
+
+	for i, item := range settingItems {
+		style := itemStyle
+		label := "  " + item.label(t)
+		if i == t.setting {
+			label = "> " + item.label(t)
+			style.Color = color.RGBA{R: 255, G: 210, B: 40, A: 255}
+		}
+		t.text.Draw(screen, label, t.area().X, itemY(i), style)
+	}
+
+	t.drawHint(screen, t.messages.Text("settings.hint"))
+}
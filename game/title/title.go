@@ -0,0 +1,391 @@
+package title
+
+import (
+	"cmp"
+	"fmt"
+	"image/color"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tejashwikalptaru/go.run/game/locale"
+	"github.com/tejashwikalptaru/go.run/game/profile"
+	"github.com/tejashwikalptaru/go.run/game/ui"
+)
+
+const (
+	logoTextSize  = 64
+	titleTextSize = 32
+	itemTextSize  = 18
+	attractDelay  = 15 * 60 // Idle frames before the demo run starts
+)
+
+// itemStyle is how the lines listed on the pages are drawn
+var itemStyle = ui.Style{Size: itemTextSize}
+
+type page int
+
+const (
+	pageMenu page = iota
+	pageStats
+	pageProfiles
+	pageEffects
+	pageScores
+	pageSettings
+)
+
+// Mode is the kind of run started from the title screen
+type Mode int
+
+const (
+	ModeLevels   Mode = iota // Levels end with a countdown to the next one and every third with a boss
+	ModeEndless              // One long run that keeps getting faster
+	ModeTutorial             // The obstacles are introduced one at a time, a run of levels follows
+)
+
+// menuItem is an entry of the main menu, the label is a message key
+type menuItem struct {
+	label  string
+	action func(t *Title)
+}
+
+var menuItems = []menuItem{
+	{label: "menu.play", action: func(t *Title) { t.start(ModeLevels) }},
+	{label: "menu.endless", action: func(t *Title) { t.start(ModeEndless) }},
+	{label: "menu.tutorial", action: func(t *Title) { t.start(ModeTutorial) }},
+	{label: "menu.scores", action: func(t *Title) { t.page = pageScores }},
+	{label: "menu.statistics", action: func(t *Title) { t.page = pageStats }},
+	{label: "menu.profiles", action: func(t *Title) { t.openProfiles() }},
+	{label: "menu.settings", action: func(t *Title) { t.page = pageSettings }},
+	{label: "menu.quit", action: func(t *Title) { t.quit = true }},
+}
+
+// Title is the screen shown before a run starts, left alone it plays a demo run behind the logo
+type Title struct {
+	text          *ui.Text
+	messages      *locale.Messages
+	profile       *profile.Profile
+	selectProfile func(name string) error // Switches the game over to another profile
+	profiles      *profilePicker
+	notice        string // Shown on the menu until the next key press, like when a save file was rejected
+	screenWidth   float64
+	screenHeight  float64
+	page          page
+	mode          Mode
+	selected      int // Selected menu item
+	setting       int // Selected setting
+	ticks         int
+	idle          int // Frames since the last key press
+	open          bool
+	quit          bool
+}
+
+func NewTitle(screenWidth, screenHeight float64, uiText *ui.Text, messages *locale.Messages, playerProfile *profile.Profile, selectProfile func(name string) error) *Title {
+	return &Title{
+		text:          uiText,
+		messages:      messages,
+		profile:       playerProfile,
+		selectProfile: selectProfile,
+		profiles:      &profilePicker{},
+		screenWidth:   screenWidth,
+		screenHeight:  screenHeight,
+		open:          true,
+	}
+}
+
+// Open shows the title screen
+func (t *Title) Open() {
+	t.open = true
+	t.page = pageMenu
+	t.idle = 0
+}
+
+// IsOpen returns true while the title screen is shown, it closes when the player starts a run
+func (t *Title) IsOpen() bool {
+	return t.open
+}
+
+// Mode returns the kind of run the player started
+func (t *Title) Mode() Mode {
+	return t.mode
+}
+
+// Quit returns true once the player picked Quit
+func (t *Title) Quit() bool {
+	return t.quit
+}
+
+// Attract returns true while the title screen was left alone long enough to play a demo run
+func (t *Title) Attract() bool {
+	return t.open && t.idle >= attractDelay
+}
+
+// SetProfile shows the statistics and high scores of another profile
+func (t *Title) SetProfile(playerProfile *profile.Profile) {
+	t.profile = playerProfile
+}
+
+// SetNotice shows a message on the menu until the player presses a key
+func (t *Title) SetNotice(msg string) {
+	t.notice = msg
+}
+
+// start closes the title screen to start a run
+func (t *Title) start(mode Mode) {
+	if mode == ModeLevels && !t.profile.TutorialDone {
+		// First-time players learn the obstacles before their first run
+		mode = ModeTutorial
+	}
+	t.mode = mode
+	t.open = false
+}
+
+func (t *Title) Update() {
+	t.ticks++
+	t.idle++
+	if len(inpututil.AppendJustPressedKeys(nil)) > 0 {
+		// Any key stops the demo run, the key itself only wakes the title screen up
+		attract := t.Attract()
+		t.idle = 0
+		t.notice = ""
+		if attract {
+			return
+		}
+	}
+	if t.Attract() {
+		return
+	}
+
+	switch t.page {
+	case pageProfiles:
+		t.updateProfiles()
+	case pageEffects:
+		t.updateEffects()
+	case pageSettings:
+		t.updateSettings()
+	case pageStats, pageScores:
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+			t.page = pageMenu
+		}
+	default:
+		t.updateMenu()
+	}
+}
+
+// updateMenu moves the selection with the arrow keys and picks the selected item with Enter or Space
+func (t *Title) updateMenu() {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp):
+		t.selected = (t.selected + len(menuItems) - 1) % len(menuItems)
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		t.selected = (t.selected + 1) % len(menuItems)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace):
+		menuItems[t.selected].action(t)
+	}
+}
+
+func (t *Title) saveSettings() {
+	if saveErr := t.profile.Save(); saveErr != nil {
+		fmt.Printf("failed to save profile: %v", saveErr)
+	}
+}
+
+// onOff describes a setting that can be turned on and off
+func (t *Title) onOff(on bool) string {
+	if on {
+		return t.messages.Text("common.on")
+	}
+	return t.messages.Text("common.off")
+}
+
+func (t *Title) Draw(screen *ebiten.Image) {
+	if t.Attract() {
+		t.drawAttract(screen)
+		return
+	}
+
+	// Darken the scene behind the title screen
+	vector.DrawFilledRect(screen, 0, 0, float32(t.screenWidth), float32(t.screenHeight), color.RGBA{A: 160}, false)
+
+	switch t.page {
+	case pageStats:
+		t.drawStats(screen)
+	case pageProfiles:
+		t.drawProfiles(screen)
+	case pageEffects:
+		t.drawEffects(screen)
+	case pageSettings:
+		t.drawSettings(screen)
+	case pageScores:
+		t.drawScores(screen)
+	default:
+		t.drawMenu(screen)
+	}
+}
+
+// area returns the part of the screen the pages are laid out in, 40 pixels off its edges
+func (t *Title) area() ui.Rect {
+	return ui.Rect{Width: t.screenWidth, Height: t.screenHeight}.Inset(40)
+}
+
+// drawHeading draws the title of a page in its top left
+func (t *Title) drawHeading(screen *ebiten.Image, msg string) {
+	t.text.Draw(screen, msg, t.area().X, 20, ui.Style{Size: titleTextSize, Color: color.RGBA{R: 255, G: 210, B: 40, A: 255}})
+}
+
+// drawHint draws the keys of a page, or a message about the last key press, centered at its bottom
+func (t *Title) drawHint(screen *ebiten.Image, msg string) {
+	t.text.DrawIn(screen, msg, t.area(), ui.Style{
+		Size:   itemTextSize,
+		Color:  color.RGBA{R: 255, A: 255},
+		Align:  ui.AlignCenter,
+		VAlign: ui.AlignEnd,
+		Wrap:   true,
+	})
+}
+
+// itemY returns the top of the item at the index of a list under the heading of a page
+func itemY(index int) float64 {
+	return 80 + float64(index)*itemStyle.LineHeight()
+}
+
+// drawLogo draws the bobbing logo with its top center at y
+func (t *Title) drawLogo(screen *ebiten.Image, y float64) {
+	bob := math.Sin(float64(t.ticks)*0.05) * 6
+	glow := 0.85 + 0.15*math.Sin(float64(t.ticks)*0.08)
+	t.text.Draw(screen, "GO.RUN", t.screenWidth/2, y+bob, ui.Style{
+		Size:         logoTextSize,
+		Color:        ui.Dim(color.RGBA{R: 255, G: 210, B: 40, A: 255}, glow),
+		Shadow:       color.RGBA{R: 120, G: 60, B: 0, A: 255},
+		ShadowOffset: 4,
+		Align:        ui.AlignCenter,
+	})
+}
+
+// drawMenu shows the logo and the menu, the selected item pulses
+func (t *Title) drawMenu(screen *ebiten.Image) {
+	t.drawLogo(screen, t.screenHeight/10)
+
+	top := t.screenHeight/10 + logoTextSize + 30
+	for i, item := range menuItems {
+		style := ui.Style{Size: itemTextSize, LineSpacing: 1.5, Align: ui.AlignCenter}
+		label := t.messages.Text(item.label)
+		if i == t.selected {
+			label = "> " + label + " <"
+			style.Color = ui.Dim(color.RGBA{R: 255, G: 210, B: 40, A: 255}, 0.75+0.25*math.Sin(float64(t.ticks)*0.15))
+		}
+		t.text.Draw(screen, label, t.screenWidth/2, top+float64(i)*style.LineHeight(), style)
+	}
+
+	if t.notice != "" {
+		t.drawHint(screen, t.notice)
+	}
+	t.text.DrawIn(screen, t.messages.Text("title.profile", t.profile.Name()), ui.Rect{Width: t.screenWidth, Height: t.screenHeight}.Inset(20), ui.Style{
+		Size:   itemTextSize * 0.75,
+		Color:  color.RGBA{R: 200, G: 200, B: 200, A: 255},
+		Align:  ui.AlignEnd,
+		VAlign: ui.AlignEnd,
+	})
+}
+
+// drawAttract shows the logo over the demo run with a blinking prompt
+func (t *Title) drawAttract(screen *ebiten.Image) {
+	t.drawLogo(screen, t.screenHeight/10)
+	if (t.ticks/30)%2 == 0 {
+		t.text.DrawIn(screen, t.messages.Text("title.demo"), ui.Rect{Width: t.screenWidth, Height: t.screenHeight}, ui.Style{
+			Size:    itemTextSize,
+			Outline: color.RGBA{A: 200}, // The demo run plays behind the prompt
+			Align:   ui.AlignCenter,
+			VAlign:  ui.AlignCenter,
+		})
+	}
+}
+
+// drawScores shows the best runs of the current profile
+func (t *Title) drawScores(screen *ebiten.Image) {
+	t.drawHeading(screen, t.messages.Text("scores.title", t.profile.Name()))
+
+	scores := ""
+	for i, h := range t.profile.HighScores {
+		scores += t.messages.Text("scores.entry", i+1, h.Score, h.Level, h.Date.Format("2006-01-02")) + "\n"
+	}
+	if len(t.profile.HighScores) == 0 {
+		scores = t.messages.Text("common.noRuns")
+	}
+	t.text.Draw(screen, scores, t.area().X, itemY(0), itemStyle)
+
+	t.drawHint(screen, t.messages.Text("common.back"))
+}
+
+// drawStats shows the lifetime totals and how often each obstacle type ended a run
+func (t *Title) drawStats(screen *ebiten.Image) {
+	lifetime := &t.profile.Lifetime
+	t.drawHeading(screen, t.messages.Text("statistics.title", t.profile.Name()))
+
+	drawLine := func(msg string, x, y float64, align ui.Align, clr color.Color) {
+		style := itemStyle
+		style.Align = align
+		style.Color = clr
+		t.text.Draw(screen, msg, x, y, style)
+	}
+
+	// Totals on the left
+	playTime := int(lifetime.PlayTime)
+	totals := []string{
+		t.messages.Text("statistics.runs", lifetime.Runs),
+		t.messages.Text("statistics.playTime", fmt.Sprintf("%d:%02d:%02d", playTime/3600, playTime/60%60, playTime%60)),
+		t.messages.Text("statistics.distance", fmt.Sprintf("%.0f", lifetime.Distance)),
+		t.messages.Text("statistics.bestLevel", lifetime.BestLevel),
+		t.messages.Text("statistics.bestScore", lifetime.BestScore),
+		t.messages.Text("statistics.jumps", lifetime.Jumps),
+		t.messages.Text("statistics.nearMisses", lifetime.NearMisses),
+	}
+	for i, line := range totals {
+		drawLine(line, t.area().X, itemY(i), ui.AlignStart, color.White)
+	}
+
+	// Deaths per obstacle type on the right, the most lethal first
+	types := make([]string, 0, len(lifetime.Deaths)+len(lifetime.Cleared))
+	for obstacleType := range lifetime.Cleared {
+		types = append(types, obstacleType)
+	}
+	for obstacleType := range lifetime.Deaths {
+		if _, ok := lifetime.Cleared[obstacleType]; !ok {
+			types = append(types, obstacleType)
+		}
+	}
+	slices.SortFunc(types, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(lifetime.Lethality(b), lifetime.Lethality(a)),
+			cmp.Compare(a, b),
+		)
+	})
+
+	// The table takes the right half of the page, the deaths column ends 180 pixels into it
+	left, right := t.screenWidth/2, t.area().X+t.area().Width
+	gold := color.RGBA{R: 255, G: 210, B: 40, A: 255}
+	drawLine(t.messages.Text("statistics.deaths"), left+180, itemY(0), ui.AlignEnd, gold)
+	drawLine(t.messages.Text("statistics.lethal"), right, itemY(0), ui.AlignEnd, gold)
+	// Pits and the boss are never cleared, they follow the obstacles without a lethality
+	others := slices.Sorted(maps.Keys(lifetime.OtherDeaths))
+	if len(types) == 0 && len(others) == 0 {
+		drawLine(t.messages.Text("common.noRuns"), left, itemY(1), ui.AlignStart, color.White)
+	}
+	for i, obstacleType := range types {
+		y := itemY(i + 1)
+		drawLine(t.messages.Text("obstacle."+obstacleType), left, y, ui.AlignStart, color.White)
+		drawLine(fmt.Sprintf("%d", lifetime.Deaths[obstacleType]), left+180, y, ui.AlignEnd, color.White)
+		drawLine(fmt.Sprintf("%.0f%%", lifetime.Lethality(obstacleType)*100), right, y, ui.AlignEnd, color.White)
+	}
+	for i, cause := range others {
+		y := itemY(len(types) + i + 1)
+		drawLine(t.messages.Text("obstacle."+cause), left, y, ui.AlignStart, color.White)
+		drawLine(fmt.Sprintf("%d", lifetime.OtherDeaths[cause]), left+180, y, ui.AlignEnd, color.White)
+		drawLine("-", right, y, ui.AlignEnd, color.White)
+	}
+
+	t.drawHint(screen, t.messages.Text("common.back"))
+}
@@ -0,0 +1,119 @@
+package transition
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/tejashwikalptaru/go.run/resources/shaders"
+)
+
+// Kind is how the frame of the previous scene gives way to the next one
+type Kind string
+
+const (
+	KindCrossfade Kind = "crossfade"
+	KindWipe      Kind = "wipe"
+	KindIris      Kind = "iris"
+	KindPixelate  Kind = "pixelate"
+)
+
+// modes are the values of the shader's Mode uniform for every kind
+var modes = map[Kind]int{
+	KindCrossfade: 0,
+	KindWipe:      1,
+	KindIris:      2,
+	KindPixelate:  3,
+}
+
+// Definition describes the transition into a level, from level 2 on, levels past the last definition reuse it
+type Definition struct {
+	Kind     Kind `json:"kind"`
+	Duration int  `json:"duration"` // Frames the transition takes
+}
+
+// LoadDefinitions parses and validates the transitions of the levels
+func LoadDefinitions(data []byte) ([]Definition, error) {
+	var definitions []Definition
+	if unmarshalErr := json.Unmarshal(data, &definitions); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	if len(definitions) == 0 {
+		return nil, fmt.Errorf("no transitions defined")
+	}
+	for i, d := range definitions {
+		if _, ok := modes[d.Kind]; !ok {
+			return nil, fmt.Errorf("level %d has a transition of unknown kind %q", i+2, d.Kind)
+		}
+		if d.Duration <= 0 {
+			return nil, fmt.Errorf("level %d needs a transition duration above zero", i+2)
+		}
+	}
+	return definitions, nil
+}
+
+// Transition blends two composed frames while the world keeps moving in both of them
+type Transition struct {
+	shader       *ebiten.Shader
+	definitions  []Definition
+	current      Definition
+	ticks        int
+	screenWidth  float64
+	screenHeight float64
+	active       bool
+}
+
+func NewTransition(screenWidth, screenHeight float64, definitions []Definition) (*Transition, error) {
+	shader, shaderErr := ebiten.NewShader(shaders.Transition)
+	if shaderErr != nil {
+		return nil, shaderErr
+	}
+	return &Transition{
+		shader:       shader,
+		definitions:  definitions,
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+	}, nil
+}
+
+// Start begins the transition into the level, the first level is never transitioned into so the first definition is
+// the one into level 2
+func (t *Transition) Start(level int) {
+	t.current = t.definitions[min(max(level-1, 1), len(t.definitions))-1]
+	t.ticks = 0
+	t.active = true
+}
+
+// Stop ends the transition right away
+func (t *Transition) Stop() {
+	t.active = false
+}
+
+// Active returns true while both frames are shown
+func (t *Transition) Active() bool {
+	return t.active
+}
+
+func (t *Transition) Update() {
+	if !t.active {
+		return
+	}
+	t.ticks++
+	if t.ticks >= t.current.Duration {
+		t.active = false
+	}
+}
+
+// Draw blends the previous frame into the next one, the iris opens around the center
+func (t *Transition) Draw(screen, previous, next *ebiten.Image, centerX, centerY float64) {
+	op := &ebiten.DrawRectShaderOptions{}
+	op.Images[0] = previous
+	op.Images[1] = next
+	op.Uniforms = map[string]any{
+		"Progress": float32(t.ticks) / float32(t.current.Duration),
+		"Mode":     modes[t.current.Kind],
+		"Center":   []float32{float32(centerX), float32(centerY)},
+		"Size":     []float32{float32(t.screenWidth), float32(t.screenHeight)},
+	}
+	screen.DrawRectShader(int(t.screenWidth), int(t.screenHeight), t.shader, op)
+}
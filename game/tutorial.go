@@ -0,0 +1,54 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tejashwikalptaru/go.run/game/tutorial"
+)
+
+// startTutorial clears the track and slows the obstacles down for the tutorial, it is practice so nothing in it counts
+func (g *Game) startTutorial() error {
+	if resetErr := g.ResetGame(); resetErr != nil {
+		return resetErr
+	}
+	g.setPractice(true)
+	g.Obstacle.Clear()
+	g.Obstacle.SetSpeed(tutorial.Speed)
+	g.Tutorial.Start()
+	return nil
+}
+
+// updateTutorial runs the runner and the obstacles sent by the tutorial, there is no countdown, no night and no game over
+func (g *Game) updateTutorial() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		return g.finishTutorial()
+	}
+	g.Scene.Update()
+	g.updateTransition()
+	g.Player.SetJumpGravity(g.Scene.JumpGravity())
+	g.Player.Update()
+	g.Obstacle.Update()
+	g.Popups.Update()
+
+	if obstacleType, send := g.Tutorial.Update(g.Obstacle.Empty()); send {
+		if spawnErr := g.Obstacle.Spawn(obstacleType); spawnErr != nil {
+			return spawnErr
+		}
+	}
+	if g.Tutorial.Finished() {
+		return g.finishTutorial()
+	}
+	return nil
+}
+
+// finishTutorial remembers that the tutorial is done and starts a run of levels
+func (g *Game) finishTutorial() error {
+	g.setPractice(false)
+	g.Profile.TutorialDone = true
+	if saveErr := g.Profile.Save(); saveErr != nil {
+		fmt.Printf("failed to save profile: %v", saveErr)
+	}
+	return g.ResetGame()
+}
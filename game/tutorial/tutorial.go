@@ -0,0 +1,179 @@
+package tutorial
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tejashwikalptaru/go.run/game/locale"
+	"github.com/tejashwikalptaru/go.run/game/ui"
+)
+
+const (
+	// Speed is the slowed down speed of the obstacles during the tutorial
+	Speed = 3.0
+
+	stepDelay     = 90  // Frames between a step being shown and its obstacle being sent
+	doneFrames    = 120 // Frames the tutorial stays on screen after the last step
+	titleTextSize = 14
+	textSize      = 18
+	maxPanelWidth = 600 // Longer prompts are wrapped onto more lines
+)
+
+// Step introduces one obstacle, its prompt is shown until the obstacle was passed without a hit, the prompts are message keys
+type Step struct {
+	Obstacle string `json:"obstacle"`
+	Prompt   string `json:"prompt"`
+	Retry    string `json:"retry"` // Shown instead of the prompt after the obstacle hit the runner
+}
+
+// LoadSteps parses and validates the steps of the tutorial
+func LoadSteps(data []byte) ([]Step, error) {
+	var steps []Step
+	if unmarshalErr := json.Unmarshal(data, &steps); unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("no tutorial steps defined")
+	}
+	for i, s := range steps {
+		if s.Obstacle == "" || s.Prompt == "" {
+			return nil, fmt.Errorf("tutorial step %d needs an obstacle and a prompt", i+1)
+		}
+	}
+	return steps, nil
+}
+
+// Tutorial sends the obstacles of its steps one at a time and repeats a step until it was passed without a hit
+type Tutorial struct {
+	text         *ui.Text
+	messages     *locale.Messages
+	steps        []Step
+	step         int
+	delay        int // Frames left until the obstacle of the step is sent
+	done         int // Frames the tutorial has been finished for
+	screenWidth  float64
+	screenHeight float64
+	active       bool
+	sent         bool // The obstacle of the step is on its way
+	failed       bool // The last attempt at the step ended in a hit
+	praised      bool // The previous step was passed, a short praise is shown while waiting
+}
+
+func NewTutorial(screenWidth, screenHeight float64, uiText *ui.Text, messages *locale.Messages, steps []Step) *Tutorial {
+	return &Tutorial{
+		text:         uiText,
+		messages:     messages,
+		steps:        steps,
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+	}
+}
+
+// Start begins the tutorial at its first step
+func (t *Tutorial) Start() {
+	t.step = 0
+	t.delay = stepDelay
+	t.done = 0
+	t.active = true
+	t.sent = false
+	t.failed = false
+	t.praised = false
+}
+
+// Stop ends the tutorial right away
+func (t *Tutorial) Stop() {
+	t.active = false
+}
+
+// Active returns true while the tutorial runs
+func (t *Tutorial) Active() bool {
+	return t.active
+}
+
+// Finished returns true once every step was passed and the closing message was shown
+func (t *Tutorial) Finished() bool {
+	return t.active && t.step >= len(t.steps) && t.done >= doneFrames
+}
+
+// Failed repeats the current step after the runner was hit
+func (t *Tutorial) Failed() {
+	if !t.sent {
+		return
+	}
+	t.sent = false
+	t.failed = true
+	t.praised = false
+	t.delay = stepDelay
+}
+
+// Update moves the tutorial along, it returns the obstacle to send when a step starts, empty is true once the track is clear
+func (t *Tutorial) Update(empty bool) (string, bool) {
+	if !t.active {
+		return "", false
+	}
+	if t.step >= len(t.steps) {
+		t.done++
+		return "", false
+	}
+	if t.sent {
+		if empty {
+			// The obstacle and everything it threw left the screen without a hit
+			t.step++
+			t.sent = false
+			t.failed = false
+			t.praised = true
+			t.delay = stepDelay
+		}
+		return "", false
+	}
+	if t.delay > 0 {
+		t.delay--
+		return "", false
+	}
+	t.sent = true
+	return t.steps[t.step].Obstacle, true
+}
+
+func (t *Tutorial) Draw(screen *ebiten.Image) {
+	if !t.active {
+		return
+	}
+
+	heading := t.messages.Text("tutorial.heading", min(t.step+1, len(t.steps)), len(t.steps))
+	msg := t.messages.Text("tutorial.finished")
+	clr := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	if t.step < len(t.steps) {
+		msg = t.messages.Text(t.steps[t.step].Prompt)
+		switch {
+		case t.failed:
+			msg = t.messages.Text(t.steps[t.step].Retry)
+			clr = color.RGBA{R: 255, G: 120, B: 100, A: 255}
+		case t.praised && !t.sent:
+			heading = t.messages.Text("tutorial.passed")
+			clr = color.RGBA{R: 255, G: 210, B: 40, A: 255}
+		}
+	}
+
+	headingStyle := ui.Style{Size: titleTextSize, Color: color.RGBA{R: 255, G: 210, B: 40, A: 255}, Align: ui.AlignCenter}
+	style := ui.Style{Size: textSize, Color: clr, Align: ui.AlignCenter, Shadow: color.RGBA{A: 200}}
+
+	// The panel grows with the prompt up to its widest, then the prompt is wrapped onto more lines
+	width := min(max(t.text.Width(msg, style)+40, 300), maxPanelWidth)
+	lines := len(t.text.Wrap(msg, textSize, width-40))
+	panel := ui.Rect{X: (t.screenWidth - width) / 2, Y: 40, Width: width, Height: 40 + float64(lines)*style.LineHeight()}
+	vector.DrawFilledRect(screen, float32(panel.X), float32(panel.Y), float32(panel.Width), float32(panel.Height), color.RGBA{A: 160}, false)
+
+	t.text.DrawIn(screen, heading, panel.Inset(8), headingStyle)
+	style.Wrap = true
+	t.text.DrawIn(screen, msg, ui.Rect{X: panel.X + 20, Y: panel.Y + 32, Width: panel.Width - 40}, style)
+
+	t.text.DrawIn(screen, t.messages.Text("tutorial.skip"), ui.Rect{Width: t.screenWidth, Height: t.screenHeight}.Inset(20), ui.Style{
+		Size:   titleTextSize,
+		Color:  color.RGBA{R: 200, G: 200, B: 200, A: 255},
+		Align:  ui.AlignEnd,
+		VAlign: ui.AlignEnd,
+	})
+}
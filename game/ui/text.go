@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/tejashwikalptaru/go.run/resources/fonts"
+)
+
+// Align is where a text is anchored, along a line or from the top down
+type Align = text.Align
+
+const (
+	AlignStart  = text.AlignStart
+	AlignCenter = text.AlignCenter
+	AlignEnd    = text.AlignEnd
+)
+
+const (
+	defaultLineSpacing  = 1.4 // Multiple of the text size between the tops of two lines
+	defaultShadowOffset = 1
+)
+
+// Rect is an area of the screen a text is placed in
+type Rect struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// Inset returns the rectangle shrunk by the padding on every side
+func (r Rect) Inset(padding float64) Rect {
+	return Rect{X: r.X + padding, Y: r.Y + padding, Width: r.Width - padding*2, Height: r.Height - padding*2}
+}
+
+// Style is how a text is drawn
+type Style struct {
+	Color        color.Color // White if left out
+	Shadow       color.Color // Drop shadow below and to the right of the text, none if left out
+	Outline      color.Color // One pixel outline around the text, none if left out
+	Size         float64
+	LineSpacing  float64 // Multiple of the size between the tops of two lines, 1.4 if left out
+	ShadowOffset float64 // How far the shadow drops, 1 pixel if left out
+	Align        Align
+	VAlign       Align
+	Wrap         bool // Break lines that don't fit the width of the rectangle they are drawn in
+}
+
+// LineHeight returns the distance between the tops of two lines
+func (s Style) LineHeight() float64 {
+	if s.LineSpacing == 0 {
+		return s.Size * defaultLineSpacing
+	}
+	return s.Size * s.LineSpacing
+}
+
+// Fade returns the color with its opacity scaled, like for texts that fade out
+func Fade(clr color.RGBA, alpha float64) color.RGBA {
+	alpha = min(max(alpha, 0), 1)
+	return color.RGBA{
+		R: uint8(float64(clr.R) * alpha),
+		G: uint8(float64(clr.G) * alpha),
+		B: uint8(float64(clr.B) * alpha),
+		A: uint8(float64(clr.A) * alpha),
+	}
+}
+
+// Dim returns the color with its brightness scaled, like for texts that pulse
+func Dim(clr color.RGBA, brightness float64) color.RGBA {
+	brightness = min(max(brightness, 0), 1)
+	return color.RGBA{
+		R: uint8(float64(clr.R) * brightness),
+		G: uint8(float64(clr.G) * brightness),
+		B: uint8(float64(clr.B) * brightness),
+		A: clr.A,
+	}
+}
+
+// outlineOffsets are the directions the outline is drawn in around the text
+var outlineOffsets = [][2]float64{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}}
+
+// Text draws texts in a font family, a face is only made once for every size
+type Text struct {
+	fontFamily *fonts.Family
+	faces      map[float64]text.Face
+}
+
+func NewText(fontFamily *fonts.Family) *Text {
+	return &Text{
+		fontFamily: fontFamily,
+		faces:      make(map[float64]text.Face),
+	}
+}
+
+// Face returns the face of the family in the size
+func (t *Text) Face(size float64) text.Face {
+	face, ok := t.faces[size]
+	if !ok {
+		face = t.fontFamily.Face(size)
+		t.faces[size] = face
+	}
+	return face
+}
+
+// Measure returns the width and the height of the text, lines are broken at line breaks only
+func (t *Text) Measure(msg string, style Style) (float64, float64) {
+	return text.Measure(msg, t.Face(style.Size), style.LineHeight())
+}
+
+// Width returns how wide the text is drawn in the style
+func (t *Text) Width(msg string, style Style) float64 {
+	width, _ := t.Measure(msg, style)
+	return width
+}
+
+// Wrap breaks the text into lines no wider than the width, between words where it can and between characters for words that don't fit a line, like in Japanese
+func (t *Text) Wrap(msg string, size, width float64) []string {
+	face := t.Face(size)
+	fits := func(line string) bool {
+		lineWidth, _ := text.Measure(line, face, 0)
+		return lineWidth <= width
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(msg, "\n") {
+		line := ""
+		for _, word := range strings.Split(paragraph, " ") {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if fits(candidate) {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			// Break a word that doesn't fit a line of its own between its characters
+			line = ""
+			for _, r := range word {
+				if line != "" && !fits(line+string(r)) {
+					lines = append(lines, line)
+					line = ""
+				}
+				line += string(r)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Draw draws the text at x and y, the alignments of the style say which side of the text is there
+func (t *Text) Draw(screen *ebiten.Image, msg string, x, y float64, style Style) {
+	face := t.Face(style.Size)
+	draw := func(dx, dy float64, clr color.Color) {
+		op := &text.DrawOptions{}
+		op.GeoM.Translate(x+dx, y+dy)
+		op.PrimaryAlign = style.Align
+		op.SecondaryAlign = style.VAlign
+		op.LineSpacing = style.LineHeight()
+		op.ColorScale.ScaleWithColor(clr)
+		text.Draw(screen, msg, face, op)
+	}
+
+	if style.Shadow != nil {
+		offset := style.ShadowOffset
+		if offset == 0 {
+			offset = defaultShadowOffset
+		}
+		draw(offset, offset, style.Shadow)
+	}
+	if style.Outline != nil {
+		for _, o := range outlineOffsets {
+			draw(o[0], o[1], style.Outline)
+		}
+	}
+	clr := style.Color
+	if clr == nil {
+		clr = color.White
+	}
+	draw(0, 0, clr)
+}
+
+// DrawIn draws the text aligned in the rectangle, with wrapping on its lines are broken to fit the width
+func (t *Text) DrawIn(screen *ebiten.Image, msg string, rect Rect, style Style) {
+	if style.Wrap {
+		msg = strings.Join(t.Wrap(msg, style.Size, rect.Width), "\n")
+	}
+	t.Draw(screen, msg, rect.X+alignOffset(style.Align, rect.Width), rect.Y+alignOffset(style.VAlign, rect.Height), style)
+}
+
+// alignOffset returns where in a span of the length a text with the alignment is anchored
+func alignOffset(align Align, length float64) float64 {
+	switch align {
+	case AlignCenter:
+		return length / 2
+	case AlignEnd:
+		return length
+	}
+	return 0
+}
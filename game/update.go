@@ -1,11 +1,61 @@
 package game
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/tejashwikalptaru/go.run/game/event"
+	"github.com/tejashwikalptaru/go.run/game/stats"
+	"github.com/tejashwikalptaru/go.run/game/title"
+)
 
 // Update handles the game logic, like jumping, obstacle movement, and collision detection
 func (g *Game) Update() error {
+	g.Display.Update()
+	g.Achievements.Update()
+	g.Camera.SetReducedMotion(g.Profile.Settings.ReducedMotion)
+	g.Camera.Update()
+	g.Messages.SetLanguage(g.Profile.Settings.Language) // The language can change in the settings or with the profile
+	g.Scene.SetWorldSpeed(g.Obstacle.Speed())           // The background speeds up along with the obstacles
+
+	if g.Title.IsOpen() {
+		return g.updateTitle()
+	}
+
 	// If the game is over, wait for the player to press space to restart
 	if g.GameOver {
+		if g.Shop.IsOpen() {
+			g.Shop.Update()
+			return nil
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyS) {
+			g.Shop.Open()
+			return nil
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			// Go back to the title screen with a fresh run waiting behind it
+			if resetErr := g.ResetGame(); resetErr != nil {
+				return resetErr
+			}
+			g.Title.Open()
+			return nil
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+			g.Summary.NextPage()
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+			g.Summary.PrevPage()
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+			path, exportErr := stats.Export(g.Stats.Run())
+			if exportErr != nil {
+				fmt.Printf("failed to export run: %v", exportErr)
+				g.Summary.SetMessage(g.Messages.Text("summary.exportFailed"))
+			} else {
+				g.Summary.SetMessage(g.Messages.Text("summary.exported", path))
+			}
+		}
 		if ebiten.IsKeyPressed(ebiten.KeySpace) {
 			// ResetToFirst the game state when space is pressed
 			if resetErr := g.ResetGame(); resetErr != nil {
@@ -16,44 +66,124 @@ func (g *Game) Update() error {
 		return nil
 	}
 
+	// Hold the world still for a moment when the runner is hit
+	if g.Camera.Frozen() {
+		return nil
+	}
+	if g.Tutorial.Active() {
+		return g.updateTutorial()
+	}
+	g.updatePlay()
+	return nil
+}
+
+// updateTitle keeps the world moving behind the title screen, left alone the title screen plays a demo run
+func (g *Game) updateTitle() error {
+	g.Title.Update()
+	switch {
+	case g.Title.Quit():
+		return ebiten.Termination
+	case g.Title.Attract():
+		if !g.demo {
+			if demoErr := g.startDemo(); demoErr != nil {
+				return demoErr
+			}
+		}
+		g.updatePlay()
+		return nil
+	case g.demo:
+		if demoErr := g.stopDemo(); demoErr != nil {
+			return demoErr
+		}
+	}
+	if !g.Title.IsOpen() {
+		g.endless = g.Title.Mode() == title.ModeEndless
+		if g.Title.Mode() == title.ModeTutorial {
+			if tutorialErr := g.startTutorial(); tutorialErr != nil {
+				return tutorialErr
+			}
+		}
+	}
+	g.Scene.Update()
+	return nil
+}
+
+// updatePlay runs the world of a run, the runner, the obstacles and the level
+func (g *Game) updatePlay() {
 	// Pause game elements during countdown
 	if g.Level.IsGreeting() {
-		g.Scene.Update() // Update background scene for visual consistency
-		g.Cloud.Update() // Continue cloud movement even during countdown
+		g.Scene.SetWorldSpeed(0)           // The world holds still with the obstacles and the pits until the countdown is over
+		g.Scene.SetGaps(g.Obstacle.Pits()) // Show the pits of the new track, not the ones left from the last level
+		g.Scene.Update()                   // The weather keeps moving
+		g.updateTransition()
 		g.Level.Update() // Handle the countdown
-		return nil       // Do not update the player or obstacles during countdown
+		return           // Do not update the player or obstacles during countdown
 	}
 
 	// Normal game updates
+	g.DayCycle.Update()
 	g.Scene.Update()
-	g.Cloud.Update()
+	g.updateTransition()
 	g.Level.Update()
+	g.Player.SetJumpGravity(g.Scene.JumpGravity())
 	g.Player.Update()
 
-	// Check if there is a collision or the obstacle is cleared
-	collision, isPowerUpCollision, obstacleCleared := g.Obstacle.Update()
-	if collision {
-		g.GameOver = !g.Player.IsImmune()
-		if !isPowerUpCollision {
-			g.MusicManager.PlayCollisionSound()
+	g.Coins.Update()    // Pick up the coins the player ran into
+	g.Obstacle.Update() // Move the obstacles, collisions and cleared obstacles are published as events
+	pits := g.Obstacle.Pits()
+	g.Scene.SetGaps(pits)
+	g.Player.SetPits(pits)
+	g.Popups.Update()
+	g.Stats.Update(g.Obstacle.Speed())
+
+	// Check for level progression, an obstacle that hit the runner is never cleared so a level also ends once its track was run through
+	if g.Level.Clear() || g.Obstacle.Empty() {
+		if g.endless {
+			g.advanceEndless()
+			return
+		}
+
+		// Boss levels end with a boss fight before the player can leave
+		if g.Level.HasBoss() && !g.Boss.Defeated() {
+			if !g.Boss.Active() {
+				g.Boss.Start(g.Level.Number() / BossEvery)
+			}
+			g.Boss.Update()
+			return
 		}
-	}
-	// Track jumps and score, and check for level progression
-	if obstacleCleared {
-		// increase score and jumps count
-		g.Level.IncreaseScore()
-	}
 
-	if g.Level.Clear() {
 		if !g.Player.WalkingToLevelExit() {
 			// if walk to level exit is done, transition to next level
+			event.Publish(g.Events, event.LevelCleared{Level: g.Level.Number(), Boss: g.Level.HasBoss()})
 			g.Scene.NextScene()
 			g.Level.Next()
-			g.Obstacle.Prepare() // reset obstacles for next level
+			g.Transition.Start(g.Level.Number())
+			event.Publish(g.Events, event.LevelStarted{Level: g.Level.Number()})
+			g.Obstacle.SetLevel(g.Level.Number())
+			g.Obstacle.IncreaseSpeed() // increase obstacle speed, before the track is built so it scrolls with the ground
+			g.Obstacle.Prepare()       // reset obstacles for next level
 			g.Player.Reset()
-			g.Obstacle.IncreaseSpeed() // increase obstacle speed
+			g.Boss.Reset()
 		}
 	}
+}
 
-	return nil
+// advanceEndless moves an endless run on to the next scene and speeds it up, without a countdown or a boss
+func (g *Game) advanceEndless() {
+	event.Publish(g.Events, event.LevelCleared{Level: g.Level.Number()})
+	g.Scene.NextScene()
+	g.Level.Advance()
+	g.Transition.Start(g.Level.Number())
+	event.Publish(g.Events, event.LevelStarted{Level: g.Level.Number()})
+	g.Obstacle.SetLevel(g.Level.Number())
+	g.Obstacle.IncreaseSpeed()
+	g.Obstacle.Extend() // the next obstacles follow the last ones at the new speed, the runner keeps running into them
+}
+
+// updateTransition moves the transition between scenes along and drops the previous scene when it is over
+func (g *Game) updateTransition() {
+	g.Transition.Update()
+	if !g.Transition.Active() {
+		g.Scene.FinishTransition()
+	}
 }
@@ -0,0 +1,160 @@
+package weather
+
+import (
+	"fmt"
+	"image/color"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/tejashwikalptaru/go.run/game/particle"
+)
+
+// Preset is a kind of weather
+type Preset string
+
+const (
+	PresetRain      Preset = "rain"
+	PresetSandstorm Preset = "sandstorm"
+	PresetSnow      Preset = "snow"
+	PresetLeaves    Preset = "leaves"
+)
+
+const (
+	// warmUpFrames fill the screen with particles before the weather is first shown
+	warmUpFrames = 300
+
+	// windLift is how much one pixel per frame of wind changes the gravity of a jump
+	windLift = 0.05
+)
+
+// Weather is a preset of particle emitters with the wind blowing through them
+type Weather struct {
+	emitters []*particle.Emitter
+	haze     color.RGBA // Tints the whole screen, like dust in the air, premultiplied by its alpha
+	wind     float64
+}
+
+// New creates the weather of a preset, intensity scales the number of particles and negative wind blows towards the
+// runner
+func New(preset Preset, intensity, wind, screenWidth, screenHeight float64, rng *rand.Rand) (*Weather, error) {
+	configs, haze, configErr := presetConfigs(preset, intensity, screenWidth, screenHeight)
+	if configErr != nil {
+		return nil, configErr
+	}
+	w := &Weather{haze: haze, wind: wind}
+	for _, config := range configs {
+		emitter := particle.NewEmitter(config, rng)
+		emitter.SetWind(wind)
+		w.emitters = append(w.emitters, emitter)
+	}
+	for i := 0; i < warmUpFrames; i++ {
+		w.Update()
+	}
+	return w, nil
+}
+
+// presetConfigs returns the emitters and the haze of a preset
+func presetConfigs(preset Preset, intensity, screenWidth, screenHeight float64) ([]particle.Config, color.RGBA, error) {
+	// Spawn beyond both edges so that particles blown sideways still cover the screen
+	spawnX, spawnWidth := -screenWidth/2, screenWidth*2
+
+	switch preset {
+	case PresetRain:
+		return []particle.Config{{
+			StartColor:   color.RGBA{R: 170, G: 190, B: 230, A: 170},
+			EndColor:     color.RGBA{R: 170, G: 190, B: 230, A: 120},
+			X:            spawnX,
+			Y:            -20,
+			Width:        spawnWidth,
+			MinVelocityY: 9,
+			MaxVelocityY: 12,
+			Rate:         4 * intensity,
+			StartSize:    1.5,
+			EndSize:      1.5,
+			MinLife:      int(screenHeight / 8),
+			MaxLife:      int(screenHeight / 8),
+			Shape:        particle.ShapeStreak,
+		}}, color.RGBA{R: 10, G: 15, B: 30, A: 40}, nil
+	case PresetSandstorm:
+		return []particle.Config{{
+			StartColor:   color.RGBA{R: 225, G: 195, B: 140, A: 220},
+			EndColor:     color.RGBA{R: 205, G: 170, B: 120, A: 0},
+			X:            screenWidth,
+			Y:            0,
+			Width:        screenWidth / 2,
+			Height:       screenHeight,
+			MinVelocityX: -5,
+			MaxVelocityX: -3,
+			MinVelocityY: -0.5,
+			MaxVelocityY: 0.5,
+			Rate:         8 * intensity,
+			StartSize:    2.5,
+			EndSize:      1,
+			Sway:         0.5,
+			MinLife:      120,
+			MaxLife:      200,
+		}}, color.RGBA{R: 55, G: 45, B: 30, A: 70}, nil
+	case PresetSnow:
+		return []particle.Config{{
+			StartColor:   color.RGBA{R: 255, G: 255, B: 255, A: 230},
+			EndColor:     color.RGBA{R: 255, G: 255, B: 255, A: 200},
+			X:            spawnX,
+			Y:            -10,
+			Width:        spawnWidth,
+			MinVelocityY: 0.8,
+			MaxVelocityY: 1.6,
+			Rate:         1.5 * intensity,
+			StartSize:    2,
+			EndSize:      3,
+			Sway:         0.6,
+			MinLife:      int(screenHeight / 0.8),
+			MaxLife:      int(screenHeight / 0.8),
+		}}, color.RGBA{R: 24, G: 25, B: 27, A: 30}, nil
+	case PresetLeaves:
+		return []particle.Config{{
+			StartColor:   color.RGBA{R: 120, G: 150, B: 50, A: 255},
+			EndColor:     color.RGBA{R: 150, G: 100, B: 40, A: 200},
+			X:            spawnX,
+			Y:            -10,
+			Width:        spawnWidth,
+			MinVelocityY: 0.8,
+			MaxVelocityY: 1.5,
+			Gravity:      0.002,
+			Rate:         0.15 * intensity,
+			StartSize:    4,
+			EndSize:      3,
+			Sway:         1.5,
+			MinLife:      300,
+			MaxLife:      400,
+		}}, color.RGBA{}, nil
+	}
+	return nil, color.RGBA{}, fmt.Errorf("unknown weather preset %q", preset)
+}
+
+// Wind returns the speed the wind blows at, negative values blow towards the runner
+func (w *Weather) Wind() float64 {
+	return w.wind
+}
+
+// JumpGravity returns the share of the gravity a jump feels in this wind, a tailwind carries the runner further and a
+// headwind cuts jumps short
+func (w *Weather) JumpGravity() float64 {
+	return min(max(1-w.wind*windLift, 0.6), 1.4)
+}
+
+func (w *Weather) Update() {
+	for _, e := range w.emitters {
+		e.Update()
+	}
+}
+
+func (w *Weather) Draw(screen *ebiten.Image) {
+	if w.haze.A > 0 {
+		bounds := screen.Bounds()
+		vector.DrawFilledRect(screen, 0, 0, float32(bounds.Dx()), float32(bounds.Dy()), w.haze, false)
+	}
+	for _, e := range w.emitters {
+		e.Draw(screen)
+	}
+}
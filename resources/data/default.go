@@ -0,0 +1,20 @@
+package data
+
+import _ "embed"
+
+var (
+	//go:embed achievements.json
+	Achievements []byte
+
+	//go:embed scenes.json
+	Scenes []byte
+
+	//go:embed levels.json
+	Levels []byte
+
+	//go:embed transitions.json
+	Transitions []byte
+
+	//go:embed tutorial.json
+	Tutorial []byte
+)
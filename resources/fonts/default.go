@@ -10,6 +10,10 @@ import (
 var (
 	//go:embed manaspace/manaspc.ttf
 	ManaSpace []byte
+
+	// MPlus covers the scripts ManaSpace doesn't, like Cyrillic and Japanese
+	//go:embed mplus/mplus-1p-regular.ttf
+	MPlus []byte
 )
 
 const (
@@ -20,3 +24,38 @@ const (
 func LoadFont(fontBytes []byte) (*text.GoTextFaceSource, error) {
 	return text.NewGoTextFaceSource(bytes.NewReader(fontBytes))
 }
+
+// Family draws text in its first font, characters the first font doesn't have are taken from the fonts after it
+type Family struct {
+	sources []*text.GoTextFaceSource
+}
+
+// LoadFamily loads the embedded primary font and the fallbacks in the order they are tried
+func LoadFamily(primary []byte, fallbacks ...[]byte) (*Family, error) {
+	family := &Family{}
+	for _, fontBytes := range append([][]byte{primary}, fallbacks...) {
+		source, sourceErr := LoadFont(fontBytes)
+		if sourceErr != nil {
+			return nil, sourceErr
+		}
+		family.sources = append(family.sources, source)
+	}
+	return family, nil
+}
+
+// Face returns a face of the family in the given size
+func (f *Family) Face(size float64) text.Face {
+	faces := make([]text.Face, len(f.sources))
+	for i, source := range f.sources {
+		faces[i] = &text.GoTextFace{
+			Source: source,
+			Size:   size,
+		}
+	}
+	face, faceErr := text.NewMultiFace(faces...)
+	if faceErr != nil {
+		// The faces of a family all run left to right, so this doesn't happen
+		return faces[0]
+	}
+	return face
+}
@@ -0,0 +1,8 @@
+package locales
+
+import "embed"
+
+// Files are the message catalogues, one per language named after its code like en.json
+//
+//go:embed *.json
+var Files embed.FS
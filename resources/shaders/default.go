@@ -0,0 +1,26 @@
+package shaders
+
+import _ "embed"
+
+var (
+	//go:embed darkness.kage
+	Darkness []byte
+
+	//go:embed transition.kage
+	Transition []byte
+
+	//go:embed crt.kage
+	CRT []byte
+
+	//go:embed vignette.kage
+	Vignette []byte
+
+	//go:embed bloom.kage
+	Bloom []byte
+
+	//go:embed aberration.kage
+	Aberration []byte
+
+	//go:embed grading.kage
+	Grading []byte
+)